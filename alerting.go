@@ -0,0 +1,361 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"gopkg.in/yaml.v3"
+)
+
+// AlertReceiverConfig maps one Alertmanager receiver onto a Jira
+// project/issue shape: where to file issues, how to render them, and which
+// transitions to drive as a group's alerts fire and resolve.
+type AlertReceiverConfig struct {
+	JiraConfig          JiraConfig `yaml:"jira_config"`
+	ProjectKey          string     `yaml:"project_key"`
+	IssueType           string     `yaml:"issue_type"`
+	Priority            string     `yaml:"priority"`
+	SummaryTemplate     string     `yaml:"summary_template"`
+	DescriptionTemplate string     `yaml:"description_template"`
+	Labels              []string   `yaml:"labels"`
+	Components          []string   `yaml:"components"`
+	// ReopenTransition is the transition to apply when a group re-fires
+	// after its issue reached DoneTransition's target status.
+	ReopenTransition string `yaml:"reopen_transition"`
+	// DoneTransition is both the transition applied when a group fully
+	// resolves and the status name checked to decide whether a re-firing
+	// group needs ReopenTransition applied.
+	DoneTransition string `yaml:"done_transition"`
+}
+
+// AlertIngressConfig is the on-disk YAML config for the /alerts ingress,
+// keyed by Alertmanager receiver name.
+type AlertIngressConfig struct {
+	Receivers map[string]AlertReceiverConfig `yaml:"receivers"`
+}
+
+func loadAlertIngressConfig(path string) (*AlertIngressConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read alert ingress config: %w", err)
+	}
+	var config AlertIngressConfig
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("failed to parse alert ingress config: %w", err)
+	}
+	return &config, nil
+}
+
+// alertIngress holds the live config plus the path it was loaded from, so
+// /-/reload can re-read it without restarting the process.
+type alertIngress struct {
+	configPath string
+	store      ConnectionStore
+
+	mu     sync.RWMutex
+	config *AlertIngressConfig
+}
+
+func newAlertIngress(configPath string, store ConnectionStore) (*alertIngress, error) {
+	ai := &alertIngress{configPath: configPath, store: store}
+	if err := ai.reload(); err != nil {
+		return nil, err
+	}
+	return ai, nil
+}
+
+func (ai *alertIngress) reload() error {
+	config, err := loadAlertIngressConfig(ai.configPath)
+	if err != nil {
+		return err
+	}
+	ai.mu.Lock()
+	ai.config = config
+	ai.mu.Unlock()
+	return nil
+}
+
+func (ai *alertIngress) receiver(name string) (AlertReceiverConfig, bool) {
+	ai.mu.RLock()
+	defer ai.mu.RUnlock()
+	r, ok := ai.config.Receivers[name]
+	return r, ok
+}
+
+// AlertmanagerAlert is one alert within an Alertmanager webhook payload.
+type AlertmanagerAlert struct {
+	Status       string            `json:"status"`
+	Labels       map[string]string `json:"labels"`
+	Annotations  map[string]string `json:"annotations"`
+	StartsAt     time.Time         `json:"startsAt"`
+	EndsAt       time.Time         `json:"endsAt"`
+	GeneratorURL string            `json:"generatorURL"`
+	Fingerprint  string            `json:"fingerprint"`
+}
+
+// AlertmanagerWebhook is the shape of Alertmanager's webhook_receiver
+// payload (the data also available to `template.Data` in its own templates).
+type AlertmanagerWebhook struct {
+	Version           string              `json:"version"`
+	GroupKey          string              `json:"groupKey"`
+	Status            string              `json:"status"`
+	Receiver          string              `json:"receiver"`
+	GroupLabels       map[string]string   `json:"groupLabels"`
+	CommonLabels      map[string]string   `json:"commonLabels"`
+	CommonAnnotations map[string]string   `json:"commonAnnotations"`
+	ExternalURL       string              `json:"externalURL"`
+	Alerts            []AlertmanagerAlert `json:"alerts"`
+}
+
+// alertTemplateData is what summary_template/description_template execute
+// against.
+type alertTemplateData struct {
+	Status            string
+	GroupLabels       map[string]string
+	CommonLabels      map[string]string
+	CommonAnnotations map[string]string
+	ExternalURL       string
+	Alerts            []AlertmanagerAlert
+}
+
+const groupKeyLabelPrefix = "jiralert_groupkey_"
+
+// groupKeyLabel turns Alertmanager's arbitrary groupKey string into a short,
+// stable value made only of characters Jira accepts in a label, so it can be
+// searched for later with a plain JQL `labels = ...` clause.
+func groupKeyLabel(groupKey string) string {
+	sum := sha256.Sum256([]byte(groupKey))
+	return fmt.Sprintf("%s%x", groupKeyLabelPrefix, sum[:8])
+}
+
+func renderAlertTemplate(tmplText string, data alertTemplateData) (string, error) {
+	tmpl, err := template.New("alert").Parse(tmplText)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse template: %w", err)
+	}
+	var buf strings.Builder
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("failed to render template: %w", err)
+	}
+	return buf.String(), nil
+}
+
+// findTransitionID looks up a transition by its own name or its destination
+// status name (case-insensitive), matching how reopen_transition/
+// done_transition are configured as status names in the YAML config.
+func findTransitionID(client *JiraClient, issueKey, transitionName string) (string, error) {
+	transitions, err := client.listTransitions(issueKey)
+	if err != nil {
+		return "", err
+	}
+	for _, t := range transitions {
+		if strings.EqualFold(t["name"], transitionName) || strings.EqualFold(t["to"], transitionName) {
+			return t["id"], nil
+		}
+	}
+	return "", fmt.Errorf("no transition to %q available for %s", transitionName, issueKey)
+}
+
+// findGroupIssue searches projectKey for an issue already tracking
+// groupLabel, returning its key and current status name ("" if none exists).
+func findGroupIssue(client *JiraClient, projectKey, groupLabel string) (key, status string, err error) {
+	jql := fmt.Sprintf(`project = %q AND labels = %q ORDER BY created DESC`, projectKey, groupLabel)
+	result, err := client.searchIssues(SearchIssuesArgs{
+		JQL:        jql,
+		Fields:     []string{"status"},
+		MaxResults: 1,
+	})
+	if err != nil {
+		return "", "", err
+	}
+	if len(result.Issues) == 0 {
+		return "", "", nil
+	}
+
+	issue := result.Issues[0]
+	key, _ = issue["key"].(string)
+	if statusField, ok := issue["status"].(map[string]interface{}); ok {
+		status, _ = statusField["name"].(string)
+	}
+	return key, status, nil
+}
+
+func createAlertIssue(client *JiraClient, receiver AlertReceiverConfig, label, summary, description string) error {
+	labels := append([]string{label}, receiver.Labels...)
+	fields := map[string]interface{}{"labels": labels}
+	if receiver.Priority != "" {
+		fields["priority"] = map[string]string{"name": receiver.Priority}
+	}
+	if len(receiver.Components) > 0 {
+		components := make([]map[string]string, 0, len(receiver.Components))
+		for _, c := range receiver.Components {
+			components = append(components, map[string]string{"name": c})
+		}
+		fields["components"] = components
+	}
+
+	issueKey, err := client.createIssue(CreateIssueArgs{
+		JiraConfig:  receiver.JiraConfig,
+		ProjectKey:  receiver.ProjectKey,
+		Summary:     summary,
+		Description: description,
+		IssueType:   receiver.IssueType,
+		Fields:      fields,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create issue for alert group: %w", err)
+	}
+	log.Printf("alert ingress: created %s for group %s", issueKey, label)
+	return nil
+}
+
+// processAlertGroup applies receiver's config to one Alertmanager webhook
+// payload: creating, reopening, commenting on, or closing out the Jira
+// issue tracking payload.GroupKey.
+func processAlertGroup(store ConnectionStore, receiver AlertReceiverConfig, payload AlertmanagerWebhook) error {
+	client, err := resolveClient(store, receiver.JiraConfig)
+	if err != nil {
+		return err
+	}
+	label := groupKeyLabel(payload.GroupKey)
+
+	data := alertTemplateData{
+		Status:            payload.Status,
+		GroupLabels:       payload.GroupLabels,
+		CommonLabels:      payload.CommonLabels,
+		CommonAnnotations: payload.CommonAnnotations,
+		ExternalURL:       payload.ExternalURL,
+		Alerts:            payload.Alerts,
+	}
+	summary, err := renderAlertTemplate(receiver.SummaryTemplate, data)
+	if err != nil {
+		return err
+	}
+	description, err := renderAlertTemplate(receiver.DescriptionTemplate, data)
+	if err != nil {
+		return err
+	}
+
+	issueKey, status, err := findGroupIssue(client, receiver.ProjectKey, label)
+	if err != nil {
+		return fmt.Errorf("failed to search for existing issue: %w", err)
+	}
+
+	if issueKey == "" {
+		if payload.Status != "firing" {
+			// A resolved notification for a group we never opened an issue
+			// for, e.g. it resolved within a single group_interval.
+			return nil
+		}
+		return createAlertIssue(client, receiver, label, summary, description)
+	}
+
+	if payload.Status == "firing" {
+		if receiver.DoneTransition != "" && receiver.ReopenTransition != "" && strings.EqualFold(status, receiver.DoneTransition) {
+			transitionID, err := findTransitionID(client, issueKey, receiver.ReopenTransition)
+			if err != nil {
+				return err
+			}
+			if err := client.transitionIssue(TransitionIssueArgs{JiraConfig: receiver.JiraConfig, IssueKey: issueKey, TransitionID: transitionID}); err != nil {
+				return fmt.Errorf("failed to reopen %s: %w", issueKey, err)
+			}
+		}
+		if _, err := client.addComment(issueKey, fmt.Sprintf("Alert group still firing:\n\n%s", description)); err != nil {
+			return fmt.Errorf("failed to comment on %s: %w", issueKey, err)
+		}
+		return nil
+	}
+
+	if _, err := client.addComment(issueKey, fmt.Sprintf("Alert group resolved:\n\n%s", description)); err != nil {
+		return fmt.Errorf("failed to comment on %s: %w", issueKey, err)
+	}
+	if receiver.DoneTransition != "" {
+		transitionID, err := findTransitionID(client, issueKey, receiver.DoneTransition)
+		if err != nil {
+			return err
+		}
+		if err := client.transitionIssue(TransitionIssueArgs{JiraConfig: receiver.JiraConfig, IssueKey: issueKey, TransitionID: transitionID}); err != nil {
+			return fmt.Errorf("failed to close %s: %w", issueKey, err)
+		}
+	}
+	return nil
+}
+
+func (ai *alertIngress) handleAlerts(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var payload AlertmanagerWebhook
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		http.Error(w, "invalid payload", http.StatusBadRequest)
+		return
+	}
+
+	receiver, ok := ai.receiver(payload.Receiver)
+	if !ok {
+		http.Error(w, fmt.Sprintf("no receiver configured for %q", payload.Receiver), http.StatusNotFound)
+		return
+	}
+
+	if err := processAlertGroup(ai.store, receiver, payload); err != nil {
+		alertProcessingErrors.Inc()
+		log.Printf("alert ingress: %v", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	alertsProcessed.Inc()
+	w.WriteHeader(http.StatusOK)
+}
+
+func (ai *alertIngress) handleHealthy(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte("OK"))
+}
+
+func (ai *alertIngress) handleReload(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if err := ai.reload(); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+// serveAlertIngress starts the optional Alertmanager-compatible ingress:
+// POST /alerts plus the /-/healthy, /-/reload, and /metrics endpoints
+// Prometheus ecosystem services (this one is modeled on jiralert) are
+// expected to expose. It runs on its own listener, like serveMetrics and
+// serveWebhook, since the MCP HTTP transport owns its endpoint exclusively.
+func serveAlertIngress(addr, configPath string, store ConnectionStore) {
+	ai, err := newAlertIngress(configPath, store)
+	if err != nil {
+		log.Printf("alert ingress disabled: %v", err)
+		return
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/alerts", ai.handleAlerts)
+	mux.HandleFunc("/-/healthy", ai.handleHealthy)
+	mux.HandleFunc("/-/reload", ai.handleReload)
+	mux.Handle("/metrics", promhttp.Handler())
+
+	log.Printf("Serving Alertmanager ingress on %s", addr)
+	if err := http.ListenAndServe(addr, mux); err != nil {
+		log.Printf("alert ingress server error: %v", err)
+	}
+}
@@ -0,0 +1,25 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGroupKeyLabelIsStableAndPrefixed(t *testing.T) {
+	a := groupKeyLabel("{}/{alertname=\"HighLatency\"}")
+	b := groupKeyLabel("{}/{alertname=\"HighLatency\"}")
+	if a != b {
+		t.Errorf("groupKeyLabel is not stable: %q != %q", a, b)
+	}
+	if !strings.HasPrefix(a, groupKeyLabelPrefix) {
+		t.Errorf("groupKeyLabel(%q) = %q, want prefix %q", "...", a, groupKeyLabelPrefix)
+	}
+}
+
+func TestGroupKeyLabelDiffersPerGroupKey(t *testing.T) {
+	a := groupKeyLabel("group-a")
+	b := groupKeyLabel("group-b")
+	if a == b {
+		t.Errorf("groupKeyLabel collided for distinct group keys: %q", a)
+	}
+}
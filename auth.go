@@ -0,0 +1,263 @@
+package main
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// AuthProvider applies credentials to an outgoing Jira REST request. Each
+// supported authentication scheme (Basic, Bearer/PAT, OAuth 1.0a, OAuth 2.0)
+// implements this so JiraClient never needs to know which one it holds.
+type AuthProvider interface {
+	// Apply mutates req in place, adding whatever headers the scheme needs.
+	Apply(req *http.Request) error
+	// Kind is the short name persisted alongside a connection, e.g. "basic".
+	Kind() string
+}
+
+// BasicAuthProvider authenticates against Jira Cloud using an account email
+// and API token.
+type BasicAuthProvider struct {
+	Email  string
+	APIKey string
+}
+
+func (a *BasicAuthProvider) Apply(req *http.Request) error {
+	req.SetBasicAuth(a.Email, a.APIKey)
+	return nil
+}
+
+func (a *BasicAuthProvider) Kind() string { return "basic" }
+
+// BearerAuthProvider authenticates using a static bearer token, e.g. a Jira
+// Data Center Personal Access Token.
+type BearerAuthProvider struct {
+	Token string
+}
+
+func (a *BearerAuthProvider) Apply(req *http.Request) error {
+	req.Header.Set("Authorization", "Bearer "+a.Token)
+	return nil
+}
+
+func (a *BearerAuthProvider) Kind() string { return "bearer" }
+
+// OAuth2PATProvider authenticates with an OAuth 2.0 access token, refreshing
+// it via the token endpoint once it is within refreshSkew of expiring.
+type OAuth2PATProvider struct {
+	TokenURL     string
+	ClientID     string
+	ClientSecret string
+	AccessToken  string
+	RefreshToken string
+	ExpiresAt    time.Time
+
+	httpClient *http.Client
+}
+
+const oauth2RefreshSkew = 60 * time.Second
+
+func (a *OAuth2PATProvider) Apply(req *http.Request) error {
+	if !a.ExpiresAt.IsZero() && time.Now().Add(oauth2RefreshSkew).After(a.ExpiresAt) {
+		if err := a.refresh(); err != nil {
+			return fmt.Errorf("refresh oauth2 token: %w", err)
+		}
+	}
+	req.Header.Set("Authorization", "Bearer "+a.AccessToken)
+	return nil
+}
+
+func (a *OAuth2PATProvider) Kind() string { return "oauth2" }
+
+func (a *OAuth2PATProvider) refresh() error {
+	client := a.httpClient
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+	form := url.Values{
+		"grant_type":    {"refresh_token"},
+		"refresh_token": {a.RefreshToken},
+		"client_id":     {a.ClientID},
+		"client_secret": {a.ClientSecret},
+	}
+	req, err := http.NewRequest("POST", a.TokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("token endpoint returned status: %d", resp.StatusCode)
+	}
+
+	var token struct {
+		AccessToken  string `json:"access_token"`
+		RefreshToken string `json:"refresh_token"`
+		ExpiresIn    int    `json:"expires_in"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&token); err != nil {
+		return fmt.Errorf("decode token response: %w", err)
+	}
+
+	a.AccessToken = token.AccessToken
+	if token.RefreshToken != "" {
+		a.RefreshToken = token.RefreshToken
+	}
+	a.ExpiresAt = time.Now().Add(time.Duration(token.ExpiresIn) * time.Second)
+	return nil
+}
+
+// OAuth1Provider signs requests with OAuth 1.0a / RSA-SHA1, the scheme Jira
+// Data Center uses for its three-legged "Application Links" handshake. Unlike
+// HMAC-SHA1, the signing key is the consumer's RSA private key, so no token
+// secret is required once an access token has been issued.
+type OAuth1Provider struct {
+	ConsumerKey string
+	PrivateKey  *rsa.PrivateKey
+	Token       string
+}
+
+func (a *OAuth1Provider) Apply(req *http.Request) error {
+	header, err := oauth1AuthHeader(req.Method, req.URL, a.ConsumerKey, a.Token, nil, a.PrivateKey)
+	if err != nil {
+		return fmt.Errorf("sign oauth1 request: %w", err)
+	}
+	req.Header.Set("Authorization", header)
+	return nil
+}
+
+func (a *OAuth1Provider) Kind() string { return "oauth1" }
+
+// oauth1AuthHeader builds a complete OAuth 1.0a Authorization header value,
+// signing with RSA-SHA1. extra carries handshake-only parameters such as
+// oauth_callback or oauth_verifier; token may be empty for the initial
+// request-token step.
+func oauth1AuthHeader(method string, reqURL *url.URL, consumerKey, token string, extra map[string]string, key *rsa.PrivateKey) (string, error) {
+	params := map[string]string{
+		"oauth_consumer_key":     consumerKey,
+		"oauth_nonce":            oauthNonce(),
+		"oauth_signature_method": "RSA-SHA1",
+		"oauth_timestamp":        strconv.FormatInt(time.Now().Unix(), 10),
+		"oauth_version":          "1.0",
+	}
+	if token != "" {
+		params["oauth_token"] = token
+	}
+	for k, v := range extra {
+		params[k] = v
+	}
+
+	sig, err := signOAuth1(method, reqURL, params, key)
+	if err != nil {
+		return "", err
+	}
+	params["oauth_signature"] = sig
+
+	return buildOAuth1Header(params), nil
+}
+
+// signOAuth1 computes the RSA-SHA1 signature over the OAuth 1.0a normalized
+// request string: method + base URL + sorted, percent-encoded parameters
+// (oauth_* params plus the request's own query parameters).
+func signOAuth1(method string, reqURL *url.URL, oauthParams map[string]string, key *rsa.PrivateKey) (string, error) {
+	all := map[string]string{}
+	for k, v := range reqURL.Query() {
+		if len(v) > 0 {
+			all[k] = v[0]
+		}
+	}
+	for k, v := range oauthParams {
+		all[k] = v
+	}
+
+	keys := make([]string, 0, len(all))
+	for k := range all {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	pairs := make([]string, 0, len(keys))
+	for _, k := range keys {
+		pairs = append(pairs, rfc3986Escape(k)+"="+rfc3986Escape(all[k]))
+	}
+
+	base := strings.ToUpper(method) + "&" +
+		rfc3986Escape(baseURLWithoutQuery(reqURL)) + "&" +
+		rfc3986Escape(strings.Join(pairs, "&"))
+
+	digest := sha1.Sum([]byte(base))
+	sig, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA1, digest[:])
+	if err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(sig), nil
+}
+
+// rfc3986Escape percent-encodes s per RFC 3986 (and thus RFC 5849's
+// signature base string requirements): unreserved characters pass through
+// unescaped, everything else is %XX-encoded, and — unlike
+// url.QueryEscape's application/x-www-form-urlencoded behaviour — a space
+// becomes %20, never a literal "+".
+func rfc3986Escape(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if isRFC3986Unreserved(c) {
+			b.WriteByte(c)
+			continue
+		}
+		fmt.Fprintf(&b, "%%%02X", c)
+	}
+	return b.String()
+}
+
+func isRFC3986Unreserved(c byte) bool {
+	return (c >= 'A' && c <= 'Z') ||
+		(c >= 'a' && c <= 'z') ||
+		(c >= '0' && c <= '9') ||
+		c == '-' || c == '.' || c == '_' || c == '~'
+}
+
+func baseURLWithoutQuery(u *url.URL) string {
+	out := *u
+	out.RawQuery = ""
+	out.Fragment = ""
+	return out.String()
+}
+
+func buildOAuth1Header(params map[string]string) string {
+	keys := make([]string, 0, len(params))
+	for k := range params {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	parts := make([]string, 0, len(keys))
+	for _, k := range keys {
+		parts = append(parts, fmt.Sprintf(`%s="%s"`, rfc3986Escape(k), rfc3986Escape(params[k])))
+	}
+	return "OAuth " + strings.Join(parts, ", ")
+}
+
+func oauthNonce() string {
+	n, _ := rand.Int(rand.Reader, big.NewInt(1<<62))
+	return strconv.FormatInt(n.Int64(), 36) + strconv.FormatInt(time.Now().UnixNano(), 36)
+}
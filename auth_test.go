@@ -0,0 +1,33 @@
+package main
+
+import "testing"
+
+func TestRFC3986EscapeUnreservedPassThrough(t *testing.T) {
+	const unreserved = "ABCxyz012-._~"
+	if got := rfc3986Escape(unreserved); got != unreserved {
+		t.Errorf("rfc3986Escape(%q) = %q, want unchanged", unreserved, got)
+	}
+}
+
+func TestRFC3986EscapeSpaceIsPercent20(t *testing.T) {
+	if got := rfc3986Escape("a b"); got != "a%20b" {
+		t.Errorf("rfc3986Escape(\"a b\") = %q, want \"a%%20b\"", got)
+	}
+}
+
+func TestRFC3986EscapeReservedCharacters(t *testing.T) {
+	if got := rfc3986Escape("a=b&c"); got != "a%3Db%26c" {
+		t.Errorf("rfc3986Escape(\"a=b&c\") = %q, want \"a%%3Db%%26c\"", got)
+	}
+}
+
+func TestOauthNonceUnique(t *testing.T) {
+	seen := map[string]bool{}
+	for i := 0; i < 100; i++ {
+		n := oauthNonce()
+		if seen[n] {
+			t.Fatalf("oauthNonce produced a duplicate: %s", n)
+		}
+		seen[n] = true
+	}
+}
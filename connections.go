@@ -0,0 +1,362 @@
+package main
+
+import (
+	"crypto/rsa"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	mcp_golang "github.com/metoro-io/mcp-golang"
+)
+
+// RegisterConnectionArgs persists a reusable Jira connection so later tool
+// calls can pass {connection_id, ...} instead of full credentials.
+type RegisterConnectionArgs struct {
+	ConnectionID string `json:"connection_id" jsonschema:"required,description=A caller-chosen identifier for this connection"`
+	URL          string `json:"url" jsonschema:"required,description=The Jira instance URL (Cloud or Data Center)"`
+
+	AuthType string `json:"auth_type" jsonschema:"required,description=One of: basic, bearer, oauth1, oauth2"`
+
+	// basic
+	Email  string `json:"email,omitempty" jsonschema:"description=Required for auth_type=basic"`
+	APIKey string `json:"api_key,omitempty" jsonschema:"description=Required for auth_type=basic or bearer"`
+
+	// oauth1 (access token leg is completed separately via jira_oauth_complete)
+	ConsumerKey string `json:"consumer_key,omitempty" jsonschema:"description=Required for auth_type=oauth1"`
+	PrivateKey  string `json:"private_key,omitempty" jsonschema:"description=PEM-encoded RSA private key, required for auth_type=oauth1"`
+
+	// oauth2
+	AccessToken  string `json:"access_token,omitempty" jsonschema:"description=Required for auth_type=oauth2"`
+	RefreshToken string `json:"refresh_token,omitempty" jsonschema:"description=Required for auth_type=oauth2"`
+	TokenURL     string `json:"token_url,omitempty" jsonschema:"description=Required for auth_type=oauth2"`
+	ClientID     string `json:"client_id,omitempty" jsonschema:"description=Required for auth_type=oauth2"`
+	ClientSecret string `json:"client_secret,omitempty" jsonschema:"description=Required for auth_type=oauth2"`
+}
+
+// ListConnectionsArgs takes no parameters; it's a struct only so it fits the
+// RegisterTool signature.
+type ListConnectionsArgs struct{}
+
+// DeleteConnectionArgs identifies the connection to remove from the store.
+type DeleteConnectionArgs struct {
+	ConnectionID string `json:"connection_id" jsonschema:"required,description=The connection to delete"`
+}
+
+// OAuthStartArgs kicks off the OAuth 1.0a three-legged handshake against a
+// Jira Data Center instance.
+type OAuthStartArgs struct {
+	ConnectionID string `json:"connection_id" jsonschema:"required,description=Identifier to save the in-progress handshake under"`
+	URL          string `json:"url" jsonschema:"required,description=The Jira Data Center instance URL"`
+	ConsumerKey  string `json:"consumer_key" jsonschema:"required,description=The Application Link consumer key"`
+	PrivateKey   string `json:"private_key" jsonschema:"required,description=PEM-encoded RSA private key matching the Application Link's public key"`
+	CallbackURL  string `json:"callback_url" jsonschema:"description=Where Jira should redirect after the user authorizes; defaults to oob"`
+}
+
+// OAuthCompleteArgs exchanges the verifier returned by the authorization URL
+// for a long-lived access token.
+type OAuthCompleteArgs struct {
+	ConnectionID string `json:"connection_id" jsonschema:"required,description=The connection_id passed to jira_oauth_start"`
+	Verifier     string `json:"verifier" jsonschema:"required,description=The oauth_verifier shown to the user after they authorize"`
+}
+
+// registerConnectionTools wires the connection-store management tools and
+// the OAuth 1.0a handshake tools onto server, backed by store.
+func registerConnectionTools(server *mcp_golang.Server, store ConnectionStore) error {
+	if err := server.RegisterTool("register_connection", "Save a reusable Jira connection (credentials) under a connection_id",
+		func(args RegisterConnectionArgs) (*mcp_golang.ToolResponse, error) {
+			conn := Connection{
+				ID:       args.ConnectionID,
+				URL:      args.URL,
+				IsCloud:  strings.Contains(strings.ToLower(args.URL), ".atlassian.net"),
+				AuthKind: args.AuthType,
+				Secrets:  map[string]string{},
+			}
+
+			switch args.AuthType {
+			case "basic":
+				conn.Secrets["email"] = args.Email
+				conn.Secrets["api_key"] = args.APIKey
+			case "bearer":
+				conn.Secrets["api_key"] = args.APIKey
+			case "oauth1":
+				if _, err := decodeRSAPrivateKeyPEM(args.PrivateKey); err != nil {
+					return nil, fmt.Errorf("invalid private_key: %w", err)
+				}
+				conn.Secrets["consumer_key"] = args.ConsumerKey
+				conn.Secrets["private_key"] = args.PrivateKey
+			case "oauth2":
+				conn.Secrets["access_token"] = args.AccessToken
+				conn.Secrets["refresh_token"] = args.RefreshToken
+				conn.Secrets["token_url"] = args.TokenURL
+				conn.Secrets["client_id"] = args.ClientID
+				conn.Secrets["client_secret"] = args.ClientSecret
+			default:
+				return nil, fmt.Errorf("unknown auth_type: %s", args.AuthType)
+			}
+
+			if err := store.Save(conn); err != nil {
+				return nil, fmt.Errorf("failed to save connection: %w", err)
+			}
+			return mcp_golang.NewToolResponse(
+				mcp_golang.NewTextContent(fmt.Sprintf("Saved connection: %s", conn.ID)),
+			), nil
+		}); err != nil {
+		return err
+	}
+
+	if err := server.RegisterTool("list_connections", "List saved Jira connection ids and their auth type",
+		func(args ListConnectionsArgs) (*mcp_golang.ToolResponse, error) {
+			conns, err := store.List()
+			if err != nil {
+				return nil, fmt.Errorf("failed to list connections: %w", err)
+			}
+
+			summaries := make([]map[string]interface{}, 0, len(conns))
+			for _, c := range conns {
+				summaries = append(summaries, map[string]interface{}{
+					"connection_id": c.ID,
+					"url":           c.URL,
+					"auth_type":     c.AuthKind,
+					"created_at":    c.CreatedAt,
+				})
+			}
+			return mcp_golang.NewToolResponse(
+				mcp_golang.NewTextContent(string(mustMarshal(summaries))),
+			), nil
+		}); err != nil {
+		return err
+	}
+
+	if err := server.RegisterTool("delete_connection", "Delete a saved Jira connection",
+		func(args DeleteConnectionArgs) (*mcp_golang.ToolResponse, error) {
+			if err := store.Delete(args.ConnectionID); err != nil {
+				return nil, fmt.Errorf("failed to delete connection: %w", err)
+			}
+			return mcp_golang.NewToolResponse(
+				mcp_golang.NewTextContent(fmt.Sprintf("Deleted connection: %s", args.ConnectionID)),
+			), nil
+		}); err != nil {
+		return err
+	}
+
+	if err := server.RegisterTool("jira_oauth_start", "Begin the OAuth 1.0a handshake against a Jira Data Center instance",
+		func(args OAuthStartArgs) (*mcp_golang.ToolResponse, error) {
+			key, err := decodeRSAPrivateKeyPEM(args.PrivateKey)
+			if err != nil {
+				return nil, fmt.Errorf("invalid private_key: %w", err)
+			}
+
+			reqToken, reqSecret, authURL, err := requestOAuth1Token(args.URL, args.ConsumerKey, key, args.CallbackURL)
+			if err != nil {
+				return nil, fmt.Errorf("failed to obtain request token: %w", err)
+			}
+
+			conn := Connection{
+				ID:       args.ConnectionID,
+				URL:      args.URL,
+				AuthKind: "oauth1-pending",
+				Secrets: map[string]string{
+					"consumer_key":   args.ConsumerKey,
+					"private_key":    args.PrivateKey,
+					"request_token":  reqToken,
+					"request_secret": reqSecret,
+				},
+			}
+			if err := store.Save(conn); err != nil {
+				return nil, fmt.Errorf("failed to save pending connection: %w", err)
+			}
+
+			return mcp_golang.NewToolResponse(
+				mcp_golang.NewTextContent(fmt.Sprintf("Visit this URL to authorize, then call jira_oauth_complete with the verifier: %s", authURL)),
+			), nil
+		}); err != nil {
+		return err
+	}
+
+	if err := server.RegisterTool("jira_oauth_complete", "Complete the OAuth 1.0a handshake using the verifier Jira returned",
+		func(args OAuthCompleteArgs) (*mcp_golang.ToolResponse, error) {
+			conn, err := store.Get(args.ConnectionID)
+			if err != nil {
+				return nil, fmt.Errorf("failed to load pending connection: %w", err)
+			}
+
+			key, err := decodeRSAPrivateKeyPEM(conn.Secrets["private_key"])
+			if err != nil {
+				return nil, fmt.Errorf("invalid stored private_key: %w", err)
+			}
+
+			accessToken, err := exchangeOAuth1Verifier(conn.URL, conn.Secrets["consumer_key"], key, conn.Secrets["request_token"], args.Verifier)
+			if err != nil {
+				return nil, fmt.Errorf("failed to exchange verifier: %w", err)
+			}
+
+			conn.AuthKind = "oauth1"
+			conn.Secrets = map[string]string{
+				"consumer_key": conn.Secrets["consumer_key"],
+				"private_key":  conn.Secrets["private_key"],
+				"access_token": accessToken,
+			}
+			if err := store.Save(conn); err != nil {
+				return nil, fmt.Errorf("failed to save completed connection: %w", err)
+			}
+
+			return mcp_golang.NewToolResponse(
+				mcp_golang.NewTextContent(fmt.Sprintf("Connection %s is now authorized", conn.ID)),
+			), nil
+		}); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// resolveClient builds a JiraClient for a tool call, preferring a connection
+// saved via register_connection (config.ConnectionID) over the inline
+// url/api_key/email fields when both are present. Every tool handler that
+// used to call NewJiraClient(args.JiraConfig) directly should go through
+// this instead, so a saved connection can actually be used for an operation.
+func resolveClient(store ConnectionStore, config JiraConfig) (*JiraClient, error) {
+	if config.ConnectionID == "" {
+		return NewJiraClient(config), nil
+	}
+
+	conn, err := store.Get(config.ConnectionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load connection %s: %w", config.ConnectionID, err)
+	}
+	return clientForConnection(conn)
+}
+
+// clientForConnection builds a JiraClient from a previously registered
+// Connection, selecting the AuthProvider that matches its AuthKind.
+func clientForConnection(conn Connection) (*JiraClient, error) {
+	var auth AuthProvider
+	switch conn.AuthKind {
+	case "basic":
+		auth = &BasicAuthProvider{Email: conn.Secrets["email"], APIKey: conn.Secrets["api_key"]}
+	case "bearer":
+		auth = &BearerAuthProvider{Token: conn.Secrets["api_key"]}
+	case "oauth1":
+		key, err := decodeRSAPrivateKeyPEM(conn.Secrets["private_key"])
+		if err != nil {
+			return nil, fmt.Errorf("invalid stored private_key: %w", err)
+		}
+		auth = &OAuth1Provider{ConsumerKey: conn.Secrets["consumer_key"], PrivateKey: key, Token: conn.Secrets["access_token"]}
+	case "oauth2":
+		auth = &OAuth2PATProvider{
+			TokenURL:     conn.Secrets["token_url"],
+			ClientID:     conn.Secrets["client_id"],
+			ClientSecret: conn.Secrets["client_secret"],
+			AccessToken:  conn.Secrets["access_token"],
+			RefreshToken: conn.Secrets["refresh_token"],
+		}
+	default:
+		return nil, fmt.Errorf("connection %s is not fully authorized (auth_kind=%s)", conn.ID, conn.AuthKind)
+	}
+
+	return NewJiraClientWithAuth(conn.URL, conn.IsCloud, auth), nil
+}
+
+// requestOAuth1Token performs the first leg of the OAuth 1.0a handshake,
+// returning a temporary request token/secret and the URL the end user should
+// visit to authorize it.
+func requestOAuth1Token(baseURL, consumerKey string, key *rsa.PrivateKey, callbackURL string) (token, secret, authURL string, err error) {
+	if callbackURL == "" {
+		callbackURL = "oob"
+	}
+
+	endpoint := strings.TrimRight(baseURL, "/") + "/plugins/servlet/oauth/request-token"
+	reqURL, err := url.Parse(endpoint)
+	if err != nil {
+		return "", "", "", err
+	}
+
+	header, err := oauth1AuthHeader("POST", reqURL, consumerKey, "", map[string]string{"oauth_callback": callbackURL}, key)
+	if err != nil {
+		return "", "", "", err
+	}
+
+	req, err := http.NewRequest("POST", endpoint, nil)
+	if err != nil {
+		return "", "", "", err
+	}
+	req.Header.Set("Authorization", header)
+
+	resp, err := (&http.Client{Timeout: 10 * time.Second}).Do(req)
+	if err != nil {
+		return "", "", "", err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", "", "", err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", "", "", fmt.Errorf("request-token endpoint returned status %d: %s", resp.StatusCode, body)
+	}
+
+	values, err := url.ParseQuery(string(body))
+	if err != nil {
+		return "", "", "", err
+	}
+
+	token = values.Get("oauth_token")
+	secret = values.Get("oauth_token_secret")
+	if token == "" {
+		return "", "", "", fmt.Errorf("request-token response missing oauth_token: %s", body)
+	}
+
+	authURL = strings.TrimRight(baseURL, "/") + "/plugins/servlet/oauth/authorize?oauth_token=" + url.QueryEscape(token)
+	return token, secret, authURL, nil
+}
+
+// exchangeOAuth1Verifier performs the third leg of the handshake, swapping
+// the request token and user-supplied verifier for a long-lived access
+// token.
+func exchangeOAuth1Verifier(baseURL, consumerKey string, key *rsa.PrivateKey, requestToken, verifier string) (string, error) {
+	endpoint := strings.TrimRight(baseURL, "/") + "/plugins/servlet/oauth/access-token"
+	reqURL, err := url.Parse(endpoint)
+	if err != nil {
+		return "", err
+	}
+
+	header, err := oauth1AuthHeader("POST", reqURL, consumerKey, requestToken, map[string]string{"oauth_verifier": verifier}, key)
+	if err != nil {
+		return "", err
+	}
+
+	req, err := http.NewRequest("POST", endpoint, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Authorization", header)
+
+	resp, err := (&http.Client{Timeout: 10 * time.Second}).Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("access-token endpoint returned status %d: %s", resp.StatusCode, body)
+	}
+
+	values, err := url.ParseQuery(string(body))
+	if err != nil {
+		return "", err
+	}
+
+	accessToken := values.Get("oauth_token")
+	if accessToken == "" {
+		return "", fmt.Errorf("access-token response missing oauth_token: %s", body)
+	}
+	return accessToken, nil
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+)
+
+func TestResolveClientUsesInlineConfigWithoutConnectionID(t *testing.T) {
+	client, err := resolveClient(nil, JiraConfig{URL: "https://example.atlassian.net", Email: "bot@example.com", APIKey: "token"})
+	if err != nil {
+		t.Fatalf("resolveClient() error = %v", err)
+	}
+	if client.config.URL != "https://example.atlassian.net" {
+		t.Errorf("resolveClient() client.config.URL = %q, want https://example.atlassian.net", client.config.URL)
+	}
+	if _, ok := client.auth.(*BasicAuthProvider); !ok {
+		t.Errorf("resolveClient() auth = %T, want *BasicAuthProvider", client.auth)
+	}
+}
+
+func TestResolveClientLoadsSavedConnection(t *testing.T) {
+	store, err := NewFileConnectionStore(filepath.Join(t.TempDir(), "connections.json"), DeriveStoreKey("test-passphrase"))
+	if err != nil {
+		t.Fatalf("NewFileConnectionStore() error = %v", err)
+	}
+	if err := store.Save(Connection{
+		ID:       "saved",
+		URL:      "https://example.atlassian.net",
+		IsCloud:  true,
+		AuthKind: "bearer",
+		Secrets:  map[string]string{"api_key": "saved-token"},
+	}); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	client, err := resolveClient(store, JiraConfig{ConnectionID: "saved"})
+	if err != nil {
+		t.Fatalf("resolveClient() error = %v", err)
+	}
+	bearer, ok := client.auth.(*BearerAuthProvider)
+	if !ok {
+		t.Fatalf("resolveClient() auth = %T, want *BearerAuthProvider", client.auth)
+	}
+	if bearer.Token != "saved-token" {
+		t.Errorf("resolveClient() bearer token = %q, want saved-token", bearer.Token)
+	}
+}
+
+func TestResolveClientUnknownConnectionIDFails(t *testing.T) {
+	store, err := NewFileConnectionStore(filepath.Join(t.TempDir(), "connections.json"), DeriveStoreKey("test-passphrase"))
+	if err != nil {
+		t.Fatalf("NewFileConnectionStore() error = %v", err)
+	}
+
+	if _, err := resolveClient(store, JiraConfig{ConnectionID: "missing"}); err == nil {
+		t.Error("resolveClient() with an unknown connection_id = nil error, want one")
+	}
+}
+
+func TestClientForConnectionDispatchesByAuthKind(t *testing.T) {
+	cases := []struct {
+		kind     string
+		conn     Connection
+		wantType string
+	}{
+		{"basic", Connection{AuthKind: "basic", Secrets: map[string]string{"email": "a@b.com", "api_key": "k"}}, "*main.BasicAuthProvider"},
+		{"bearer", Connection{AuthKind: "bearer", Secrets: map[string]string{"api_key": "k"}}, "*main.BearerAuthProvider"},
+		{"oauth2", Connection{AuthKind: "oauth2", Secrets: map[string]string{"access_token": "t"}}, "*main.OAuth2PATProvider"},
+	}
+	for _, c := range cases {
+		client, err := clientForConnection(c.conn)
+		if err != nil {
+			t.Fatalf("clientForConnection(%s) error = %v", c.kind, err)
+		}
+		if got := fmt.Sprintf("%T", client.auth); got != c.wantType {
+			t.Errorf("clientForConnection(%s) auth type = %s, want %s", c.kind, got, c.wantType)
+		}
+	}
+}
+
+func TestClientForConnectionUnknownAuthKindFails(t *testing.T) {
+	if _, err := clientForConnection(Connection{ID: "c1", AuthKind: "oauth1-pending"}); err == nil {
+		t.Error("clientForConnection() with an unfinished auth_kind = nil error, want one")
+	}
+}
@@ -0,0 +1,163 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// RPCError is returned for any Jira REST call that comes back with a
+// non-2xx status, carrying the decoded `errorMessages`/`errors` object Jira
+// returns alongside the raw body so callers (and MCP tool errors) don't have
+// to re-parse it.
+type RPCError struct {
+	Status        int
+	Method        string
+	URL           string
+	ErrorMessages []string
+	Errors        map[string]string
+	Body          string
+
+	// CircuitOpen is set for requests doRPC refused to send because the
+	// per-host circuit breaker is open; Status is meaningless in that case
+	// since no HTTP response was ever received.
+	CircuitOpen bool
+}
+
+// Error formats the message mcp-golang sends back to the MCP caller: this
+// library's ToolResponse error path only ever carries Error()'s string (see
+// toolResponseSent.MarshalJSON in mcp-golang/server.go, which discards the
+// error value itself and keeps only its text), so Code() is embedded as a
+// leading "[jira.xxx]" prefix rather than left for the caller to recompute
+// from a status code it never sees.
+func (e *RPCError) Error() string {
+	if e.CircuitOpen {
+		return fmt.Sprintf("[%s] %s %s: %s", e.Code(), e.Method, e.URL, e.ErrorMessages[0])
+	}
+	if len(e.ErrorMessages) > 0 {
+		return fmt.Sprintf("[%s] %s %s: status %d: %s", e.Code(), e.Method, e.URL, e.Status, e.ErrorMessages[0])
+	}
+	return fmt.Sprintf("[%s] %s %s: status %d", e.Code(), e.Method, e.URL, e.Status)
+}
+
+// Code maps the HTTP status onto a stable, machine-readable category MCP
+// tool callers can branch on without inspecting Status directly.
+func (e *RPCError) Code() string {
+	switch {
+	case e.CircuitOpen:
+		return "jira.circuit_open"
+	case e.Status == http.StatusUnauthorized, e.Status == http.StatusForbidden:
+		return "jira.auth"
+	case e.Status == http.StatusNotFound:
+		return "jira.not_found"
+	case e.Status == http.StatusTooManyRequests:
+		return "jira.rate_limited"
+	case e.Status == http.StatusBadRequest, e.Status == http.StatusUnprocessableEntity:
+		return "jira.validation"
+	default:
+		return "jira.unknown"
+	}
+}
+
+// newRPCError decodes Jira's standard error payload ({errorMessages, errors})
+// from resp.Body, falling back to the raw body text if it isn't JSON.
+func newRPCError(method, reqURL string, status int, body []byte) *RPCError {
+	rpcErr := &RPCError{Method: method, URL: reqURL, Status: status, Body: string(body)}
+
+	var decoded struct {
+		ErrorMessages []string          `json:"errorMessages"`
+		Errors        map[string]string `json:"errors"`
+	}
+	if err := json.Unmarshal(body, &decoded); err == nil {
+		rpcErr.ErrorMessages = decoded.ErrorMessages
+		rpcErr.Errors = decoded.Errors
+	}
+	return rpcErr
+}
+
+const (
+	maxRPCRetries       = 3
+	retryBaseDelay      = 250 * time.Millisecond
+	circuitFailureMax   = 5
+	circuitOpenCooldown = 30 * time.Second
+)
+
+// isRetryableStatus reports whether status is worth retrying with backoff:
+// rate limiting and transient upstream/gateway failures.
+func isRetryableStatus(status int) bool {
+	switch status {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	default:
+		return false
+	}
+}
+
+// retryDelay computes how long to wait before retrying attempt (0-indexed),
+// honouring a Retry-After header when Jira sends one.
+func retryDelay(attempt int, retryAfter string) time.Duration {
+	if retryAfter != "" {
+		if secs, err := strconv.Atoi(retryAfter); err == nil {
+			return time.Duration(secs) * time.Second
+		}
+	}
+	return retryBaseDelay * time.Duration(1<<attempt)
+}
+
+// circuitBreaker trips after circuitFailureMax consecutive failures against
+// a single host and stays open for circuitOpenCooldown, so a bad Jira
+// instance fails fast instead of tying up every worker in retry loops.
+type circuitBreaker struct {
+	mu        sync.Mutex
+	failures  int
+	openUntil time.Time
+}
+
+func (b *circuitBreaker) allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return time.Now().After(b.openUntil)
+}
+
+func (b *circuitBreaker) recordSuccess() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.failures = 0
+	b.openUntil = time.Time{}
+}
+
+func (b *circuitBreaker) recordFailure() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.failures++
+	if b.failures >= circuitFailureMax {
+		b.openUntil = time.Now().Add(circuitOpenCooldown)
+	}
+}
+
+// circuitBreakers holds one circuitBreaker per Jira host, shared across all
+// JiraClient instances in the process.
+var circuitBreakers = struct {
+	mu     sync.Mutex
+	byHost map[string]*circuitBreaker
+}{byHost: map[string]*circuitBreaker{}}
+
+func circuitBreakerFor(rawURL string) *circuitBreaker {
+	host := rawURL
+	if parsed, err := url.Parse(rawURL); err == nil && parsed.Host != "" {
+		host = parsed.Host
+	}
+
+	circuitBreakers.mu.Lock()
+	defer circuitBreakers.mu.Unlock()
+	b, ok := circuitBreakers.byHost[host]
+	if !ok {
+		b = &circuitBreaker{}
+		circuitBreakers.byHost[host] = b
+	}
+	return b
+}
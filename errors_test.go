@@ -0,0 +1,75 @@
+package main
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRPCErrorCode(t *testing.T) {
+	cases := []struct {
+		status int
+		want   string
+	}{
+		{http.StatusUnauthorized, "jira.auth"},
+		{http.StatusForbidden, "jira.auth"},
+		{http.StatusNotFound, "jira.not_found"},
+		{http.StatusTooManyRequests, "jira.rate_limited"},
+		{http.StatusBadRequest, "jira.validation"},
+		{http.StatusUnprocessableEntity, "jira.validation"},
+		{http.StatusInternalServerError, "jira.unknown"},
+	}
+	for _, c := range cases {
+		err := &RPCError{Status: c.status}
+		if got := err.Code(); got != c.want {
+			t.Errorf("RPCError{Status: %d}.Code() = %q, want %q", c.status, got, c.want)
+		}
+	}
+}
+
+func TestRPCErrorMessageIncludesCode(t *testing.T) {
+	err := newRPCError("GET", "https://jira.example.com/rest/api/2/issue/PROJ-1", http.StatusNotFound, nil)
+	if !strings.HasPrefix(err.Error(), "[jira.not_found]") {
+		t.Errorf("RPCError.Error() = %q, want it to start with \"[jira.not_found]\"", err.Error())
+	}
+}
+
+func TestRPCErrorCircuitOpenCode(t *testing.T) {
+	err := &RPCError{Method: "GET", URL: "https://jira.example.com/rest/api/2/issue/PROJ-1", CircuitOpen: true, ErrorMessages: []string{"circuit breaker open for https://jira.example.com"}}
+	if got := err.Code(); got != "jira.circuit_open" {
+		t.Errorf("RPCError{CircuitOpen: true}.Code() = %q, want \"jira.circuit_open\"", got)
+	}
+	if !strings.HasPrefix(err.Error(), "[jira.circuit_open]") {
+		t.Errorf("RPCError.Error() = %q, want it to start with \"[jira.circuit_open]\"", err.Error())
+	}
+}
+
+func TestIsRetryableStatus(t *testing.T) {
+	for _, status := range []int{http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout} {
+		if !isRetryableStatus(status) {
+			t.Errorf("isRetryableStatus(%d) = false, want true", status)
+		}
+	}
+	for _, status := range []int{http.StatusBadRequest, http.StatusNotFound, http.StatusOK} {
+		if isRetryableStatus(status) {
+			t.Errorf("isRetryableStatus(%d) = true, want false", status)
+		}
+	}
+}
+
+func TestRetryDelayHonoursRetryAfter(t *testing.T) {
+	got := retryDelay(0, "2")
+	if got != 2*time.Second {
+		t.Errorf("retryDelay(0, \"2\") = %v, want 2s", got)
+	}
+}
+
+func TestRetryDelayExponentialBackoff(t *testing.T) {
+	d0 := retryDelay(0, "")
+	d1 := retryDelay(1, "")
+	d2 := retryDelay(2, "")
+	if d1 != d0*2 || d2 != d0*4 {
+		t.Errorf("retryDelay backoff = %v, %v, %v; want doubling each attempt", d0, d1, d2)
+	}
+}
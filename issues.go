@@ -0,0 +1,503 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"path/filepath"
+
+	mcp_golang "github.com/metoro-io/mcp-golang"
+)
+
+// TransitionIssueArgs applies a workflow transition to an issue. Use
+// list_transitions first to discover the valid transition IDs for the
+// issue's current status.
+type TransitionIssueArgs struct {
+	JiraConfig   JiraConfig `json:"jira_config" jsonschema:"required,description=Jira connection configuration"`
+	IssueKey     string     `json:"issue_key" jsonschema:"required,description=The key of the issue to transition (e.g., PROJ-123)"`
+	TransitionID string     `json:"transition_id" jsonschema:"required,description=The id of the transition to apply, as returned by list_transitions"`
+	Comment      string     `json:"comment" jsonschema:"description=Optional comment to add while transitioning"`
+}
+
+// ListTransitionsArgs lists the workflow transitions available for an issue.
+type ListTransitionsArgs struct {
+	JiraConfig JiraConfig `json:"jira_config" jsonschema:"required,description=Jira connection configuration"`
+	IssueKey   string     `json:"issue_key" jsonschema:"required,description=The key of the issue (e.g., PROJ-123)"`
+}
+
+// AddCommentArgs adds a comment to an issue.
+type AddCommentArgs struct {
+	JiraConfig JiraConfig `json:"jira_config" jsonschema:"required,description=Jira connection configuration"`
+	IssueKey   string     `json:"issue_key" jsonschema:"required,description=The key of the issue (e.g., PROJ-123)"`
+	Body       string     `json:"body" jsonschema:"required,description=The comment text"`
+}
+
+// ListCommentsArgs lists the comments on an issue.
+type ListCommentsArgs struct {
+	JiraConfig JiraConfig `json:"jira_config" jsonschema:"required,description=Jira connection configuration"`
+	IssueKey   string     `json:"issue_key" jsonschema:"required,description=The key of the issue (e.g., PROJ-123)"`
+}
+
+// EditCommentArgs replaces the body of an existing comment.
+type EditCommentArgs struct {
+	JiraConfig JiraConfig `json:"jira_config" jsonschema:"required,description=Jira connection configuration"`
+	IssueKey   string     `json:"issue_key" jsonschema:"required,description=The key of the issue (e.g., PROJ-123)"`
+	CommentID  string     `json:"comment_id" jsonschema:"required,description=The id of the comment to edit"`
+	Body       string     `json:"body" jsonschema:"required,description=The new comment text"`
+}
+
+// DeleteCommentArgs removes a comment from an issue.
+type DeleteCommentArgs struct {
+	JiraConfig JiraConfig `json:"jira_config" jsonschema:"required,description=Jira connection configuration"`
+	IssueKey   string     `json:"issue_key" jsonschema:"required,description=The key of the issue (e.g., PROJ-123)"`
+	CommentID  string     `json:"comment_id" jsonschema:"required,description=The id of the comment to delete"`
+}
+
+// AddAttachmentArgs uploads a local file as an attachment on an issue.
+type AddAttachmentArgs struct {
+	JiraConfig JiraConfig `json:"jira_config" jsonschema:"required,description=Jira connection configuration"`
+	IssueKey   string     `json:"issue_key" jsonschema:"required,description=The key of the issue (e.g., PROJ-123)"`
+	FilePath   string     `json:"file_path" jsonschema:"required,description=Path to the local file to attach"`
+}
+
+// LinkIssuesArgs creates a link between two issues, e.g. "PROJ-1 blocks PROJ-2".
+type LinkIssuesArgs struct {
+	JiraConfig JiraConfig `json:"jira_config" jsonschema:"required,description=Jira connection configuration"`
+	InwardKey  string     `json:"inward_key" jsonschema:"required,description=The key of the inward issue (e.g., PROJ-1)"`
+	OutwardKey string     `json:"outward_key" jsonschema:"required,description=The key of the outward issue (e.g., PROJ-2)"`
+	LinkType   string     `json:"link_type" jsonschema:"required,description=The link type name (e.g., Blocks, Relates)"`
+}
+
+// AssignIssueArgs assigns an issue to a user.
+type AssignIssueArgs struct {
+	JiraConfig JiraConfig `json:"jira_config" jsonschema:"required,description=Jira connection configuration"`
+	IssueKey   string     `json:"issue_key" jsonschema:"required,description=The key of the issue (e.g., PROJ-123)"`
+	AccountID  string     `json:"account_id" jsonschema:"required,description=The account id (Cloud) or username (Data Center) to assign to"`
+}
+
+// AssignComponentsArgs replaces the components set on an issue.
+type AssignComponentsArgs struct {
+	JiraConfig JiraConfig `json:"jira_config" jsonschema:"required,description=Jira connection configuration"`
+	IssueKey   string     `json:"issue_key" jsonschema:"required,description=The key of the issue (e.g., PROJ-123)"`
+	Components []string   `json:"components" jsonschema:"required,description=Component names to set on the issue"`
+}
+
+// ListComponentsArgs lists the components defined on a project.
+type ListComponentsArgs struct {
+	JiraConfig JiraConfig `json:"jira_config" jsonschema:"required,description=Jira connection configuration"`
+	ProjectKey string     `json:"project_key" jsonschema:"required,description=The key of the project"`
+}
+
+// listTransitions returns the workflow transitions available for an issue.
+func (c *JiraClient) listTransitions(issueKey string) ([]map[string]string, error) {
+	var result struct {
+		Transitions []struct {
+			ID   string `json:"id"`
+			Name string `json:"name"`
+			To   struct {
+				Name string `json:"name"`
+			} `json:"to"`
+		} `json:"transitions"`
+	}
+	status, err := c.doRPC("GET", "/issue/"+issueKey+"/transitions", nil, &result)
+	if err != nil {
+		return nil, err
+	}
+	if status != http.StatusOK {
+		return nil, fmt.Errorf("failed to list transitions, status: %d", status)
+	}
+
+	transitions := make([]map[string]string, 0, len(result.Transitions))
+	for _, t := range result.Transitions {
+		transitions = append(transitions, map[string]string{
+			"id":   t.ID,
+			"name": t.Name,
+			"to":   t.To.Name,
+		})
+	}
+	return transitions, nil
+}
+
+// transitionIssue applies a workflow transition to an issue, optionally
+// adding a comment in the same request.
+func (c *JiraClient) transitionIssue(args TransitionIssueArgs) error {
+	payload := map[string]interface{}{
+		"transition": map[string]string{"id": args.TransitionID},
+	}
+	if args.Comment != "" {
+		payload["update"] = map[string]interface{}{
+			"comment": []map[string]interface{}{
+				{"add": map[string]string{"body": args.Comment}},
+			},
+		}
+	}
+
+	status, err := c.doRPC("POST", "/issue/"+args.IssueKey+"/transitions", payload, nil)
+	if err != nil {
+		return err
+	}
+	if status != http.StatusNoContent {
+		return fmt.Errorf("failed to transition issue, status: %d", status)
+	}
+	return nil
+}
+
+// addComment adds a comment to an issue, returning the new comment's id.
+func (c *JiraClient) addComment(issueKey, body string) (string, error) {
+	var result struct {
+		ID string `json:"id"`
+	}
+	status, err := c.doRPC("POST", "/issue/"+issueKey+"/comment", map[string]string{"body": body}, &result)
+	if err != nil {
+		return "", err
+	}
+	if status != http.StatusCreated {
+		return "", fmt.Errorf("failed to add comment, status: %d", status)
+	}
+	return result.ID, nil
+}
+
+// listComments lists the comments on an issue.
+func (c *JiraClient) listComments(issueKey string) ([]map[string]string, error) {
+	var result struct {
+		Comments []struct {
+			ID   string `json:"id"`
+			Body string `json:"body"`
+		} `json:"comments"`
+	}
+	status, err := c.doRPC("GET", "/issue/"+issueKey+"/comment", nil, &result)
+	if err != nil {
+		return nil, err
+	}
+	if status != http.StatusOK {
+		return nil, fmt.Errorf("failed to list comments, status: %d", status)
+	}
+
+	comments := make([]map[string]string, 0, len(result.Comments))
+	for _, c := range result.Comments {
+		comments = append(comments, map[string]string{"id": c.ID, "body": c.Body})
+	}
+	return comments, nil
+}
+
+// editComment replaces the body of an existing comment.
+func (c *JiraClient) editComment(issueKey, commentID, body string) error {
+	status, err := c.doRPC("PUT", "/issue/"+issueKey+"/comment/"+commentID, map[string]string{"body": body}, nil)
+	if err != nil {
+		return err
+	}
+	if status != http.StatusOK {
+		return fmt.Errorf("failed to edit comment, status: %d", status)
+	}
+	return nil
+}
+
+// deleteComment removes a comment from an issue.
+func (c *JiraClient) deleteComment(issueKey, commentID string) error {
+	status, err := c.doRPC("DELETE", "/issue/"+issueKey+"/comment/"+commentID, nil, nil)
+	if err != nil {
+		return err
+	}
+	if status != http.StatusNoContent {
+		return fmt.Errorf("failed to delete comment, status: %d", status)
+	}
+	return nil
+}
+
+// addAttachment uploads filePath as a multipart attachment on an issue. This
+// bypasses doRPC since the Jira attachments endpoint needs a multipart body
+// and the non-standard X-Atlassian-Token header rather than a JSON payload.
+func (c *JiraClient) addAttachment(issueKey, filePath string) (string, error) {
+	f, err := os.Open(filePath)
+	if err != nil {
+		return "", fmt.Errorf("failed to open file: %v", err)
+	}
+	defer f.Close()
+
+	var buf bytes.Buffer
+	writer := multipart.NewWriter(&buf)
+	part, err := writer.CreateFormFile("file", filepath.Base(filePath))
+	if err != nil {
+		return "", fmt.Errorf("failed to create form file: %v", err)
+	}
+	if _, err := io.Copy(part, f); err != nil {
+		return "", fmt.Errorf("failed to copy file contents: %v", err)
+	}
+	if err := writer.Close(); err != nil {
+		return "", fmt.Errorf("failed to close multipart writer: %v", err)
+	}
+
+	url := fmt.Sprintf("%s%s/issue/%s/attachments", c.config.URL, c.getBaseAPIPath(), issueKey)
+	req, err := http.NewRequest("POST", url, &buf)
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %v", err)
+	}
+	if err := c.auth.Apply(req); err != nil {
+		return "", fmt.Errorf("failed to apply auth: %v", err)
+	}
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	req.Header.Set("X-Atlassian-Token", "nocheck")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to send request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("failed to add attachment, status: %d", resp.StatusCode)
+	}
+
+	var result []struct {
+		ID string `json:"id"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return "", fmt.Errorf("failed to decode response: %v", err)
+	}
+	if len(result) == 0 {
+		return "", fmt.Errorf("attachment response did not include an id")
+	}
+	return result[0].ID, nil
+}
+
+// linkIssues creates a link of linkType between two issues.
+func (c *JiraClient) linkIssues(args LinkIssuesArgs) error {
+	payload := map[string]interface{}{
+		"type":         map[string]string{"name": args.LinkType},
+		"inwardIssue":  map[string]string{"key": args.InwardKey},
+		"outwardIssue": map[string]string{"key": args.OutwardKey},
+	}
+	status, err := c.doRPC("POST", "/issueLink", payload, nil)
+	if err != nil {
+		return err
+	}
+	if status != http.StatusCreated {
+		return fmt.Errorf("failed to link issues, status: %d", status)
+	}
+	return nil
+}
+
+// assignIssue assigns an issue to the given account.
+func (c *JiraClient) assignIssue(issueKey, accountID string) error {
+	field := "accountId"
+	if !c.isCloud {
+		field = "name"
+	}
+	status, err := c.doRPC("PUT", "/issue/"+issueKey+"/assignee", map[string]string{field: accountID}, nil)
+	if err != nil {
+		return err
+	}
+	if status != http.StatusNoContent {
+		return fmt.Errorf("failed to assign issue, status: %d", status)
+	}
+	return nil
+}
+
+// assignComponents replaces the components set on an issue.
+func (c *JiraClient) assignComponents(issueKey string, components []string) error {
+	values := make([]map[string]string, 0, len(components))
+	for _, name := range components {
+		values = append(values, map[string]string{"name": name})
+	}
+	payload := map[string]interface{}{
+		"fields": map[string]interface{}{"components": values},
+	}
+	status, err := c.doRPC("PUT", "/issue/"+issueKey, payload, nil)
+	if err != nil {
+		return err
+	}
+	if status != http.StatusNoContent && status != http.StatusOK {
+		return fmt.Errorf("failed to assign components, status: %d", status)
+	}
+	return nil
+}
+
+// listComponents lists the components defined on a project.
+func (c *JiraClient) listComponents(projectKey string) ([]map[string]string, error) {
+	var result []struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+	}
+	status, err := c.doRPC("GET", "/project/"+projectKey+"/components", nil, &result)
+	if err != nil {
+		return nil, err
+	}
+	if status != http.StatusOK {
+		return nil, fmt.Errorf("failed to list components, status: %d", status)
+	}
+
+	components := make([]map[string]string, 0, len(result))
+	for _, comp := range result {
+		components = append(components, map[string]string{"id": comp.ID, "name": comp.Name})
+	}
+	return components, nil
+}
+
+// registerIssueTools wires the transition/comment/attachment/link/component/
+// assignment tools onto server.
+func registerIssueTools(server *mcp_golang.Server, store ConnectionStore) error {
+	if err := server.RegisterTool("list_transitions", "List the workflow transitions available for a Jira issue",
+		func(args ListTransitionsArgs) (*mcp_golang.ToolResponse, error) {
+			client, err := resolveClient(store, args.JiraConfig)
+			if err != nil {
+				return nil, err
+			}
+			transitions, err := client.listTransitions(args.IssueKey)
+			if err != nil {
+				return nil, err
+			}
+			return mcp_golang.NewToolResponse(mcp_golang.NewTextContent(string(mustMarshal(transitions)))), nil
+		}); err != nil {
+		return err
+	}
+
+	if err := server.RegisterTool("transition_issue", "Apply a workflow transition to a Jira issue",
+		func(args TransitionIssueArgs) (*mcp_golang.ToolResponse, error) {
+			client, err := resolveClient(store, args.JiraConfig)
+			if err != nil {
+				return nil, err
+			}
+			if err := client.transitionIssue(args); err != nil {
+				return nil, err
+			}
+			return mcp_golang.NewToolResponse(mcp_golang.NewTextContent(fmt.Sprintf("Transitioned issue: %s", args.IssueKey))), nil
+		}); err != nil {
+		return err
+	}
+
+	if err := server.RegisterTool("add_comment", "Add a comment to a Jira issue",
+		func(args AddCommentArgs) (*mcp_golang.ToolResponse, error) {
+			client, err := resolveClient(store, args.JiraConfig)
+			if err != nil {
+				return nil, err
+			}
+			commentID, err := client.addComment(args.IssueKey, args.Body)
+			if err != nil {
+				return nil, err
+			}
+			return mcp_golang.NewToolResponse(mcp_golang.NewTextContent(fmt.Sprintf("Added comment: %s", commentID))), nil
+		}); err != nil {
+		return err
+	}
+
+	if err := server.RegisterTool("list_comments", "List the comments on a Jira issue",
+		func(args ListCommentsArgs) (*mcp_golang.ToolResponse, error) {
+			client, err := resolveClient(store, args.JiraConfig)
+			if err != nil {
+				return nil, err
+			}
+			comments, err := client.listComments(args.IssueKey)
+			if err != nil {
+				return nil, err
+			}
+			return mcp_golang.NewToolResponse(mcp_golang.NewTextContent(string(mustMarshal(comments)))), nil
+		}); err != nil {
+		return err
+	}
+
+	if err := server.RegisterTool("edit_comment", "Edit an existing comment on a Jira issue",
+		func(args EditCommentArgs) (*mcp_golang.ToolResponse, error) {
+			client, err := resolveClient(store, args.JiraConfig)
+			if err != nil {
+				return nil, err
+			}
+			if err := client.editComment(args.IssueKey, args.CommentID, args.Body); err != nil {
+				return nil, err
+			}
+			return mcp_golang.NewToolResponse(mcp_golang.NewTextContent(fmt.Sprintf("Edited comment: %s", args.CommentID))), nil
+		}); err != nil {
+		return err
+	}
+
+	if err := server.RegisterTool("delete_comment", "Delete a comment from a Jira issue",
+		func(args DeleteCommentArgs) (*mcp_golang.ToolResponse, error) {
+			client, err := resolveClient(store, args.JiraConfig)
+			if err != nil {
+				return nil, err
+			}
+			if err := client.deleteComment(args.IssueKey, args.CommentID); err != nil {
+				return nil, err
+			}
+			return mcp_golang.NewToolResponse(mcp_golang.NewTextContent(fmt.Sprintf("Deleted comment: %s", args.CommentID))), nil
+		}); err != nil {
+		return err
+	}
+
+	if err := server.RegisterTool("add_attachment", "Upload a local file as an attachment on a Jira issue",
+		func(args AddAttachmentArgs) (*mcp_golang.ToolResponse, error) {
+			client, err := resolveClient(store, args.JiraConfig)
+			if err != nil {
+				return nil, err
+			}
+			attachmentID, err := client.addAttachment(args.IssueKey, args.FilePath)
+			if err != nil {
+				return nil, err
+			}
+			return mcp_golang.NewToolResponse(mcp_golang.NewTextContent(fmt.Sprintf("Added attachment: %s", attachmentID))), nil
+		}); err != nil {
+		return err
+	}
+
+	if err := server.RegisterTool("link_issues", "Create a link between two Jira issues",
+		func(args LinkIssuesArgs) (*mcp_golang.ToolResponse, error) {
+			client, err := resolveClient(store, args.JiraConfig)
+			if err != nil {
+				return nil, err
+			}
+			if err := client.linkIssues(args); err != nil {
+				return nil, err
+			}
+			return mcp_golang.NewToolResponse(mcp_golang.NewTextContent(fmt.Sprintf("Linked %s %s %s", args.InwardKey, args.LinkType, args.OutwardKey))), nil
+		}); err != nil {
+		return err
+	}
+
+	if err := server.RegisterTool("assign_issue", "Assign a Jira issue to a user",
+		func(args AssignIssueArgs) (*mcp_golang.ToolResponse, error) {
+			client, err := resolveClient(store, args.JiraConfig)
+			if err != nil {
+				return nil, err
+			}
+			if err := client.assignIssue(args.IssueKey, args.AccountID); err != nil {
+				return nil, err
+			}
+			return mcp_golang.NewToolResponse(mcp_golang.NewTextContent(fmt.Sprintf("Assigned issue %s to %s", args.IssueKey, args.AccountID))), nil
+		}); err != nil {
+		return err
+	}
+
+	if err := server.RegisterTool("assign_components", "Set the components on a Jira issue",
+		func(args AssignComponentsArgs) (*mcp_golang.ToolResponse, error) {
+			client, err := resolveClient(store, args.JiraConfig)
+			if err != nil {
+				return nil, err
+			}
+			if err := client.assignComponents(args.IssueKey, args.Components); err != nil {
+				return nil, err
+			}
+			return mcp_golang.NewToolResponse(mcp_golang.NewTextContent(fmt.Sprintf("Updated components on issue: %s", args.IssueKey))), nil
+		}); err != nil {
+		return err
+	}
+
+	if err := server.RegisterTool("list_components", "List the components defined on a Jira project",
+		func(args ListComponentsArgs) (*mcp_golang.ToolResponse, error) {
+			client, err := resolveClient(store, args.JiraConfig)
+			if err != nil {
+				return nil, err
+			}
+			components, err := client.listComponents(args.ProjectKey)
+			if err != nil {
+				return nil, err
+			}
+			return mcp_golang.NewToolResponse(mcp_golang.NewTextContent(string(mustMarshal(components)))), nil
+		}); err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func newTestIssueClient(t *testing.T, handler http.HandlerFunc) *JiraClient {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	return NewJiraClientWithAuth(server.URL, true, &BearerAuthProvider{Token: "test-token"})
+}
+
+func TestListTransitionsFlattensToNameAndDestination(t *testing.T) {
+	client := newTestIssueClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"transitions": [
+			{"id": "11", "name": "In Progress", "to": {"name": "In Progress"}},
+			{"id": "21", "name": "Done", "to": {"name": "Done"}}
+		]}`))
+	})
+
+	transitions, err := client.listTransitions("PROJ-1")
+	if err != nil {
+		t.Fatalf("listTransitions() error = %v", err)
+	}
+	want := []map[string]string{
+		{"id": "11", "name": "In Progress", "to": "In Progress"},
+		{"id": "21", "name": "Done", "to": "Done"},
+	}
+	if len(transitions) != len(want) {
+		t.Fatalf("listTransitions() = %+v, want %+v", transitions, want)
+	}
+	for i := range want {
+		if transitions[i]["id"] != want[i]["id"] || transitions[i]["name"] != want[i]["name"] || transitions[i]["to"] != want[i]["to"] {
+			t.Errorf("listTransitions()[%d] = %+v, want %+v", i, transitions[i], want[i])
+		}
+	}
+}
+
+func TestListCommentsFlattensToIDAndBody(t *testing.T) {
+	client := newTestIssueClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"comments": [{"id": "10001", "body": "first"}, {"id": "10002", "body": "second"}]}`))
+	})
+
+	comments, err := client.listComments("PROJ-1")
+	if err != nil {
+		t.Fatalf("listComments() error = %v", err)
+	}
+	if len(comments) != 2 || comments[0]["id"] != "10001" || comments[0]["body"] != "first" || comments[1]["id"] != "10002" || comments[1]["body"] != "second" {
+		t.Errorf("listComments() = %+v, want id/body pairs for both comments", comments)
+	}
+}
+
+func TestListComponentsFlattensToIDAndName(t *testing.T) {
+	client := newTestIssueClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[{"id": "100", "name": "Backend"}, {"id": "200", "name": "Frontend"}]`))
+	})
+
+	components, err := client.listComponents("PROJ")
+	if err != nil {
+		t.Fatalf("listComponents() error = %v", err)
+	}
+	if len(components) != 2 || components[0]["id"] != "100" || components[0]["name"] != "Backend" || components[1]["id"] != "200" || components[1]["name"] != "Frontend" {
+		t.Errorf("listComponents() = %+v, want id/name pairs for both components", components)
+	}
+}
+
+func TestAssignComponentsSendsFieldsShapedPayload(t *testing.T) {
+	var gotBody map[string]interface{}
+	client := newTestIssueClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&gotBody); err != nil {
+			t.Fatalf("decoding request body: %v", err)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	if err := client.assignComponents("PROJ-1", []string{"Backend", "Frontend"}); err != nil {
+		t.Fatalf("assignComponents() error = %v", err)
+	}
+
+	fields, ok := gotBody["fields"].(map[string]interface{})
+	if !ok {
+		t.Fatalf("assignComponents() payload = %+v, want a top-level \"fields\" object", gotBody)
+	}
+	components, ok := fields["components"].([]interface{})
+	if !ok || len(components) != 2 {
+		t.Fatalf("assignComponents() fields.components = %+v, want two component objects", fields["components"])
+	}
+	first, ok := components[0].(map[string]interface{})
+	if !ok || first["name"] != "Backend" {
+		t.Errorf("assignComponents() fields.components[0] = %+v, want name=Backend", components[0])
+	}
+}
+
+func TestAssignIssueUsesAccountIDFieldForCloud(t *testing.T) {
+	var gotBody map[string]interface{}
+	client := newTestIssueClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&gotBody); err != nil {
+			t.Fatalf("decoding request body: %v", err)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	if err := client.assignIssue("PROJ-1", "account-123"); err != nil {
+		t.Fatalf("assignIssue() error = %v", err)
+	}
+	if gotBody["accountId"] != "account-123" {
+		t.Errorf("assignIssue() payload = %+v, want accountId=account-123", gotBody)
+	}
+}
@@ -1,10 +1,13 @@
 package main
 
 import (
+	"bytes"
 	"encoding/json"
 	"flag"
 	"fmt"
+	"io"
 	"log"
+	"os"
 	"strings"
 	"time"
 
@@ -15,39 +18,42 @@ import (
 )
 
 const (
-	defaultAppPort = 8080
-	ServerName     = "Jira MCP Server"
-	ServerVersion  = "1.0.0"
+	defaultAppPort     = 8080
+	defaultMetricsAddr = ":9090"
+	defaultWebhookAddr = ":9091"
+	defaultAlertAddr   = ":9092"
+	defaultStorePath   = "jira-connections.json"
+	ServerName         = "Jira MCP Server"
+	ServerVersion      = "1.0.0"
 )
 
-// JiraConfig holds Jira connection details
+// JiraConfig holds Jira connection details. Either ConnectionID alone, or
+// URL/APIKey/Email, must be set; resolveClient prefers ConnectionID when
+// both are present.
 type JiraConfig struct {
-	URL    string `json:"url" jsonschema:"required,description=The Jira instance URL (Cloud or Data Center)"`
-	APIKey string `json:"api_key" jsonschema:"required,description=The Jira API key or Personal Access Token"`
-	Email  string `json:"email" jsonschema:"required,description=The email address for Jira Cloud authentication"`
+	ConnectionID string `json:"connection_id,omitempty" jsonschema:"description=A connection id saved via register_connection; when set, url/api_key/email are ignored"`
+	URL          string `json:"url,omitempty" jsonschema:"description=The Jira instance URL (Cloud or Data Center); required unless connection_id is set"`
+	APIKey       string `json:"api_key,omitempty" jsonschema:"description=The Jira API key or Personal Access Token; required unless connection_id is set"`
+	Email        string `json:"email,omitempty" jsonschema:"description=The email address for Jira Cloud authentication; required unless connection_id is set"`
 }
 
 // CreateIssueArgs defines arguments for creating a Jira issue
 type CreateIssueArgs struct {
-	JiraConfig  JiraConfig `json:"jira_config" jsonschema:"required,description=Jira connection configuration"`
-	ProjectKey  string     `json:"project_key" jsonschema:"required,description=The key of the project to create the issue in"`
-	Summary     string     `json:"summary" jsonschema:"required,description=The summary or title of the issue"`
-	Description string     `json:"description" jsonschema:"description=Optional description of the issue"`
-	IssueType   string     `json:"issue_type" jsonschema:"required,description=The type of issue (e.g., Bug, Story, Task)"`
+	JiraConfig  JiraConfig             `json:"jira_config" jsonschema:"required,description=Jira connection configuration"`
+	ProjectKey  string                 `json:"project_key" jsonschema:"required,description=The key of the project to create the issue in"`
+	Summary     string                 `json:"summary" jsonschema:"required,description=The summary or title of the issue"`
+	Description string                 `json:"description" jsonschema:"description=Optional description of the issue"`
+	IssueType   string                 `json:"issue_type" jsonschema:"required,description=The type of issue (e.g., Bug, Story, Task)"`
+	Fields      map[string]interface{} `json:"fields" jsonschema:"description=Additional fields to set, keyed by display name (e.g. 'Story Points') or field id (e.g. customfield_10001); see get_create_metadata"`
 }
 
 // UpdateIssueArgs defines arguments for updating a Jira issue
 type UpdateIssueArgs struct {
-	JiraConfig  JiraConfig `json:"jira_config" jsonschema:"required,description=Jira connection configuration"`
-	IssueKey    string     `json:"issue_key" jsonschema:"required,description=The key of the issue to update (e.g., PROJ-123)"`
-	Summary     string     `json:"summary" jsonschema:"description=The new summary or title of the issue"`
-	Description string     `json:"description" jsonschema:"description=The new description of the issue"`
-}
-
-// SearchIssuesArgs defines arguments for searching Jira issues
-type SearchIssuesArgs struct {
-	JiraConfig JiraConfig `json:"jira_config" jsonschema:"required,description=Jira connection configuration"`
-	JQL        string     `json:"jql" jsonschema:"required,description=The JQL query to search issues (e.g., 'project = PROJ AND status = Open')"`
+	JiraConfig  JiraConfig             `json:"jira_config" jsonschema:"required,description=Jira connection configuration"`
+	IssueKey    string                 `json:"issue_key" jsonschema:"required,description=The key of the issue to update (e.g., PROJ-123)"`
+	Summary     string                 `json:"summary" jsonschema:"description=The new summary or title of the issue"`
+	Description string                 `json:"description" jsonschema:"description=The new description of the issue"`
+	Fields      map[string]interface{} `json:"fields" jsonschema:"description=Additional fields to set, keyed by display name (e.g. 'Story Points') or field id (e.g. customfield_10001); see get_edit_metadata"`
 }
 
 // JiraClient encapsulates Jira API interactions
@@ -55,15 +61,39 @@ type JiraClient struct {
 	config     JiraConfig
 	httpClient *http.Client
 	isCloud    bool
+	auth       AuthProvider
 }
 
-// NewJiraClient initializes a Jira client
+// NewJiraClient initializes a Jira client from an inline JiraConfig, picking
+// Basic (Cloud) or Bearer (Data Center) auth the way this server has always
+// behaved. Use NewJiraClientWithAuth for connections backed by the
+// connection store, which may use OAuth 1.0a or OAuth 2.0 instead.
 func NewJiraClient(config JiraConfig) *JiraClient {
 	isCloud := strings.Contains(strings.ToLower(config.URL), ".atlassian.net")
+
+	var auth AuthProvider
+	if isCloud {
+		auth = &BasicAuthProvider{Email: config.Email, APIKey: config.APIKey}
+	} else {
+		auth = &BearerAuthProvider{Token: config.APIKey}
+	}
+
 	return &JiraClient{
 		config:     config,
 		httpClient: &http.Client{Timeout: 10 * time.Second},
 		isCloud:    isCloud,
+		auth:       auth,
+	}
+}
+
+// NewJiraClientWithAuth initializes a Jira client against baseURL using an
+// explicit AuthProvider, for connections resolved from the connection store.
+func NewJiraClientWithAuth(baseURL string, isCloud bool, auth AuthProvider) *JiraClient {
+	return &JiraClient{
+		config:     JiraConfig{URL: baseURL},
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+		isCloud:    isCloud,
+		auth:       auth,
 	}
 }
 
@@ -75,52 +105,120 @@ func (c *JiraClient) getBaseAPIPath() string {
 	return "/rest/api/2" // Data Center typically uses /api/2, but some endpoints may vary
 }
 
-// createIssue creates a new issue in Jira
-func (c *JiraClient) createIssue(args CreateIssueArgs) (string, error) {
-	url := fmt.Sprintf("%s%s/issue", c.config.URL, c.getBaseAPIPath())
-	payload := map[string]interface{}{
-		"fields": map[string]interface{}{
-			"project": map[string]string{
-				"key": args.ProjectKey,
-			},
-			"summary":     args.Summary,
-			"description": args.Description,
-			"issuetype": map[string]string{
-				"name": args.IssueType,
-			},
-		},
+// doRPC builds and sends a Jira REST request, centralizing the parts every
+// call needs: URL assembly, auth, content type, and response decoding. path
+// is relative to getBaseAPIPath(), e.g. "/issue/PROJ-1". body is marshaled
+// as the JSON request body if non-nil; target is decoded from the JSON
+// response body if non-nil. The caller is responsible for checking
+// resp.StatusCode via the returned status code before trusting target.
+func (c *JiraClient) doRPC(method, path string, body interface{}, target interface{}) (int, error) {
+	fullURL := fmt.Sprintf("%s%s%s", c.config.URL, c.getBaseAPIPath(), path)
+
+	var reqBody []byte
+	if body != nil {
+		var err error
+		reqBody, err = json.Marshal(body)
+		if err != nil {
+			return 0, fmt.Errorf("failed to marshal payload: %v", err)
+		}
 	}
 
-	body, err := json.Marshal(payload)
-	if err != nil {
-		return "", fmt.Errorf("failed to marshal payload: %v", err)
+	breaker := circuitBreakerFor(c.config.URL)
+	if !breaker.allow() {
+		return 0, &RPCError{Method: method, URL: fullURL, CircuitOpen: true, ErrorMessages: []string{fmt.Sprintf("circuit breaker open for %s", c.config.URL)}}
 	}
 
-	req, err := http.NewRequest("POST", url, strings.NewReader(string(body)))
-	if err != nil {
-		return "", fmt.Errorf("failed to create request: %v", err)
+	var lastErr error
+	for attempt := 0; attempt <= maxRPCRetries; attempt++ {
+		req, err := http.NewRequest(method, fullURL, bytes.NewReader(reqBody))
+		if err != nil {
+			return 0, fmt.Errorf("failed to create request: %v", err)
+		}
+		if err := c.auth.Apply(req); err != nil {
+			return 0, fmt.Errorf("failed to apply auth: %v", err)
+		}
+		if body != nil {
+			req.Header.Set("Content-Type", "application/json")
+		}
+		req.Header.Set("Accept", "application/json")
+
+		start := time.Now()
+		resp, err := c.httpClient.Do(req)
+		if err != nil {
+			breaker.recordFailure()
+			return 0, fmt.Errorf("failed to send request: %v", err)
+		}
+
+		respBody, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		recordRPCDuration(path, time.Since(start).Seconds())
+		if err != nil {
+			breaker.recordFailure()
+			return resp.StatusCode, fmt.Errorf("failed to read response: %v", err)
+		}
+
+		if resp.StatusCode >= 400 {
+			breaker.recordFailure()
+			rpcErr := newRPCError(method, fullURL, resp.StatusCode, respBody)
+			recordRPCError(path, rpcErr.Code())
+
+			if isRetryableStatus(resp.StatusCode) && attempt < maxRPCRetries {
+				lastErr = rpcErr
+				time.Sleep(retryDelay(attempt, resp.Header.Get("Retry-After")))
+				continue
+			}
+			return resp.StatusCode, rpcErr
+		}
+
+		breaker.recordSuccess()
+
+		if target != nil && len(respBody) > 0 {
+			if err := json.Unmarshal(respBody, target); err != nil {
+				return resp.StatusCode, fmt.Errorf("failed to decode response: %v", err)
+			}
+		}
+		return resp.StatusCode, nil
 	}
 
-	if c.isCloud {
-		req.SetBasicAuth(c.config.Email, c.config.APIKey)
-	} else {
-		req.Header.Set("Authorization", "Bearer "+c.config.APIKey)
+	return 0, lastErr
+}
+
+// createIssue creates a new issue in Jira
+func (c *JiraClient) createIssue(args CreateIssueArgs) (string, error) {
+	fields := map[string]interface{}{
+		"project": map[string]string{
+			"key": args.ProjectKey,
+		},
+		"summary": args.Summary,
+		"issuetype": map[string]string{
+			"name": args.IssueType,
+		},
+	}
+	if args.Description != "" {
+		if c.isCloud {
+			fields["description"] = adfDescription(args.Description)
+		} else {
+			fields["description"] = args.Description
+		}
 	}
-	req.Header.Set("Content-Type", "application/json")
 
-	resp, err := c.httpClient.Do(req)
+	extra, err := c.resolveFields(args.Fields)
 	if err != nil {
-		return "", fmt.Errorf("failed to send request: %v", err)
+		return "", fmt.Errorf("failed to resolve fields: %v", err)
 	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusCreated {
-		return "", fmt.Errorf("failed to create issue, status: %d", resp.StatusCode)
+	for id, value := range extra {
+		fields[id] = value
 	}
 
+	payload := map[string]interface{}{"fields": fields}
+
 	var result map[string]interface{}
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return "", fmt.Errorf("failed to decode response: %v", err)
+	status, err := c.doRPC("POST", "/issue", payload, &result)
+	if err != nil {
+		return "", err
+	}
+	if status != http.StatusCreated {
+		return "", fmt.Errorf("failed to create issue, status: %d", status)
 	}
 
 	issueKey, ok := result["key"].(string)
@@ -133,104 +231,76 @@ func (c *JiraClient) createIssue(args CreateIssueArgs) (string, error) {
 
 // updateIssue updates an existing issue in Jira
 func (c *JiraClient) updateIssue(args UpdateIssueArgs) (string, error) {
-	url := fmt.Sprintf("%s%s/issue/%s", c.config.URL, c.getBaseAPIPath(), args.IssueKey)
-	payload := map[string]interface{}{
-		"fields": map[string]interface{}{},
-	}
-
+	fields := map[string]interface{}{}
 	if args.Summary != "" {
-		payload["fields"].(map[string]interface{})["summary"] = args.Summary
+		fields["summary"] = args.Summary
 	}
 	if args.Description != "" {
-		payload["fields"].(map[string]interface{})["description"] = args.Description
+		if c.isCloud {
+			fields["description"] = adfDescription(args.Description)
+		} else {
+			fields["description"] = args.Description
+		}
 	}
 
-	body, err := json.Marshal(payload)
+	extra, err := c.resolveFields(args.Fields)
 	if err != nil {
-		return "", fmt.Errorf("failed to marshal payload: %v", err)
+		return "", fmt.Errorf("failed to resolve fields: %v", err)
 	}
-
-	req, err := http.NewRequest("PUT", url, strings.NewReader(string(body)))
-	if err != nil {
-		return "", fmt.Errorf("failed to create request: %v", err)
+	for id, value := range extra {
+		fields[id] = value
 	}
 
-	if c.isCloud {
-		req.SetBasicAuth(c.config.Email, c.config.APIKey)
-	} else {
-		req.Header.Set("Authorization", "Bearer "+c.config.APIKey)
-	}
-	req.Header.Set("Content-Type", "application/json")
+	payload := map[string]interface{}{"fields": fields}
 
-	resp, err := c.httpClient.Do(req)
+	status, err := c.doRPC("PUT", "/issue/"+args.IssueKey, payload, nil)
 	if err != nil {
-		return "", fmt.Errorf("failed to send request: %v", err)
+		return "", err
 	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("failed to update issue, status: %d", resp.StatusCode)
+	if status != http.StatusNoContent && status != http.StatusOK {
+		return "", fmt.Errorf("failed to update issue, status: %d", status)
 	}
 
 	return args.IssueKey, nil
 }
 
-// searchIssues searches issues using JQL
-func (c *JiraClient) searchIssues(args SearchIssuesArgs) ([]map[string]string, error) {
-	url := fmt.Sprintf("%s%s/search?jql=%s&fields=summary", c.config.URL, c.getBaseAPIPath(), args.JQL)
-	req, err := http.NewRequest("GET", url, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %v", err)
-	}
-
-	if c.isCloud {
-		req.SetBasicAuth(c.config.Email, c.config.APIKey)
-	} else {
-		req.Header.Set("Authorization", "Bearer "+c.config.APIKey)
-	}
-	req.Header.Set("Content-Type", "application/json")
-
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("failed to send request: %v", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("failed to search issues, status: %d", resp.StatusCode)
-	}
-
-	var result struct {
-		Issues []struct {
-			Key    string `json:"key"`
-			Fields struct {
-				Summary string `json:"summary"`
-			} `json:"fields"`
-		} `json:"issues"`
-	}
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %v", err)
-	}
-
-	issues := make([]map[string]string, 0, len(result.Issues))
-	for _, issue := range result.Issues {
-		issues = append(issues, map[string]string{
-			"key":     issue.Key,
-			"summary": issue.Fields.Summary,
-		})
-	}
-
-	return issues, nil
-}
-
 func main() {
 	log.Println("Starting Jira MCP Server...")
 
 	// Parse command line arguments
 	var appPort int
+	var storePath string
+	var storeKey string
+	var metricsAddr string
+	var webhookAddr string
+	var webhookSecret string
+	var alertAddr string
+	var alertConfigPath string
 	flag.IntVar(&appPort, "port", defaultAppPort, "The port to listen on")
+	flag.StringVar(&storePath, "store-path", defaultStorePath, "Path to the connection store file")
+	flag.StringVar(&storeKey, "store-key", os.Getenv("JIRA_MCP_STORE_KEY"), "Passphrase used to encrypt the connection store at rest")
+	flag.StringVar(&metricsAddr, "metrics-addr", defaultMetricsAddr, "Address to serve Prometheus metrics on")
+	flag.StringVar(&webhookAddr, "webhook-addr", defaultWebhookAddr, "Address to serve the /jira/webhook receiver on")
+	flag.StringVar(&webhookSecret, "webhook-secret", os.Getenv("JIRA_MCP_WEBHOOK_SECRET"), "Shared secret Jira must send in the X-Jira-Webhook-Secret header")
+	flag.StringVar(&alertAddr, "alert-addr", defaultAlertAddr, "Address to serve the Alertmanager ingress on")
+	flag.StringVar(&alertConfigPath, "alert-config", "", "Path to the Alertmanager ingress YAML config; leave unset to disable the ingress")
 	flag.Parse()
 
+	if storeKey == "" {
+		log.Fatal("a connection store key is required: pass -store-key or set JIRA_MCP_STORE_KEY")
+	}
+
+	store, err := NewFileConnectionStore(storePath, DeriveStoreKey(storeKey))
+	if err != nil {
+		log.Fatalf("Error opening connection store: %v", err)
+	}
+
+	go serveMetrics(metricsAddr)
+	go serveWebhook(webhookAddr, webhookSecret)
+	if alertConfigPath != "" {
+		go serveAlertIngress(alertAddr, alertConfigPath, store)
+	}
+
 	log.Printf("Listening on port: %d", appPort)
 
 	// Initialize MCP server with HTTP transport
@@ -238,8 +308,12 @@ func main() {
 	transport.WithAddr(fmt.Sprintf(":%d", appPort))
 	server := mcp_golang.NewServer(transport)
 
+	if err := registerConnectionTools(server, store); err != nil {
+		log.Fatalf("Error registering connection tools: %v", err)
+	}
+
 	// Register server info endpoint
-	err := server.RegisterResource("info://server", "Server Information", "Provides details about the server and available actions", "application/json",
+	err = server.RegisterResource("info://server", "Server Information", "Provides details about the server and available actions", "application/json",
 		func() (*mcp_golang.ResourceResponse, error) {
 			info := map[string]interface{}{
 				"name":    ServerName,
@@ -248,6 +322,29 @@ func main() {
 					"create_issue",
 					"update_issue",
 					"search_issues",
+					"register_connection",
+					"list_connections",
+					"delete_connection",
+					"jira_oauth_start",
+					"jira_oauth_complete",
+					"list_transitions",
+					"transition_issue",
+					"add_comment",
+					"list_comments",
+					"edit_comment",
+					"delete_comment",
+					"add_attachment",
+					"link_issues",
+					"assign_issue",
+					"assign_components",
+					"list_components",
+					"get_create_metadata",
+					"get_edit_metadata",
+					"search_issues_stream",
+					"subscribe_issues",
+					"unsubscribe_issues",
+					"poll_subscription",
+					"register_webhook",
 				},
 			}
 			return mcp_golang.NewResourceResponse(
@@ -265,7 +362,10 @@ func main() {
 	// Register create issue tool
 	err = server.RegisterTool("create_issue", "Create a new Jira issue",
 		func(args CreateIssueArgs) (*mcp_golang.ToolResponse, error) {
-			client := NewJiraClient(args.JiraConfig)
+			client, err := resolveClient(store, args.JiraConfig)
+			if err != nil {
+				return nil, err
+			}
 			issueKey, err := client.createIssue(args)
 			if err != nil {
 				return nil, err
@@ -281,7 +381,10 @@ func main() {
 	// Register update issue tool
 	err = server.RegisterTool("update_issue", "Update an existing Jira issue",
 		func(args UpdateIssueArgs) (*mcp_golang.ToolResponse, error) {
-			client := NewJiraClient(args.JiraConfig)
+			client, err := resolveClient(store, args.JiraConfig)
+			if err != nil {
+				return nil, err
+			}
 			issueKey, err := client.updateIssue(args)
 			if err != nil {
 				return nil, err
@@ -294,20 +397,20 @@ func main() {
 		log.Fatalf("Error registering update_issue tool: %v", err)
 	}
 
-	// Register search issues tool
-	err = server.RegisterTool("search_issues", "Search Jira issues using JQL",
-		func(args SearchIssuesArgs) (*mcp_golang.ToolResponse, error) {
-			client := NewJiraClient(args.JiraConfig)
-			issues, err := client.searchIssues(args)
-			if err != nil {
-				return nil, err
-			}
-			return mcp_golang.NewToolResponse(
-				mcp_golang.NewTextContent(string(mustMarshal(issues))),
-			), nil
-		})
-	if err != nil {
-		log.Fatalf("Error registering search_issues tool: %v", err)
+	if err := registerSearchTools(server, store); err != nil {
+		log.Fatalf("Error registering search tools: %v", err)
+	}
+
+	if err := registerIssueTools(server, store); err != nil {
+		log.Fatalf("Error registering issue tools: %v", err)
+	}
+
+	if err := registerMetadataTools(server, store); err != nil {
+		log.Fatalf("Error registering metadata tools: %v", err)
+	}
+
+	if err := registerWebhookTools(server, store); err != nil {
+		log.Fatalf("Error registering webhook tools: %v", err)
 	}
 
 	// Start the server
@@ -0,0 +1,347 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+
+	mcp_golang "github.com/metoro-io/mcp-golang"
+)
+
+// GetCreateMetadataArgs fetches the fields (and their allowed values) that a
+// project/issue type requires before creating an issue.
+type GetCreateMetadataArgs struct {
+	JiraConfig JiraConfig `json:"jira_config" jsonschema:"required,description=Jira connection configuration"`
+	ProjectKey string     `json:"project_key" jsonschema:"required,description=The key of the project"`
+	IssueType  string     `json:"issue_type" jsonschema:"description=Restrict to a single issue type name (e.g., Bug); returns all issue types if omitted"`
+}
+
+// GetEditMetadataArgs fetches the fields an existing issue can be edited to.
+type GetEditMetadataArgs struct {
+	JiraConfig JiraConfig `json:"jira_config" jsonschema:"required,description=Jira connection configuration"`
+	IssueKey   string     `json:"issue_key" jsonschema:"required,description=The key of the issue (e.g., PROJ-123)"`
+}
+
+// fieldMeta is the subset of Jira's createmeta/editmeta field schema that
+// callers need to build a valid request.
+type fieldMeta struct {
+	Required      bool     `json:"required"`
+	Name          string   `json:"name"`
+	Key           string   `json:"key"`
+	AllowedValues []string `json:"allowedValues,omitempty"`
+	SchemaType    string   `json:"schemaType,omitempty"`
+}
+
+// getCreateMetadata returns the allowed fields for creating an issue in
+// projectKey, optionally narrowed to a single issueType. Cloud and Data
+// Center expose this very differently: Cloud has a dedicated
+// createmeta/{project}/issuetypes endpoint, while Data Center only offers
+// the legacy expand-based createmeta.
+func (c *JiraClient) getCreateMetadata(projectKey, issueType string) ([]fieldMeta, error) {
+	if c.isCloud {
+		return c.getCreateMetadataCloud(projectKey, issueType)
+	}
+	return c.getCreateMetadataLegacy(projectKey, issueType)
+}
+
+func (c *JiraClient) getCreateMetadataCloud(projectKey, issueType string) ([]fieldMeta, error) {
+	var issueTypes struct {
+		IssueTypes []struct {
+			ID   string `json:"id"`
+			Name string `json:"name"`
+		} `json:"issueTypes"`
+	}
+	status, err := c.doRPC("GET", "/issue/createmeta/"+projectKey+"/issuetypes", nil, &issueTypes)
+	if err != nil {
+		return nil, err
+	}
+	if status != http.StatusOK {
+		return nil, fmt.Errorf("failed to get createmeta issue types, status: %d", status)
+	}
+
+	var fields []fieldMeta
+	for _, it := range issueTypes.IssueTypes {
+		if issueType != "" && !strings.EqualFold(it.Name, issueType) {
+			continue
+		}
+
+		var resp struct {
+			Fields []struct {
+				FieldID       string `json:"fieldId"`
+				Name          string `json:"name"`
+				Required      bool   `json:"required"`
+				AllowedValues []struct {
+					Value string `json:"value"`
+					Name  string `json:"name"`
+				} `json:"allowedValues"`
+				Schema struct {
+					Type string `json:"type"`
+				} `json:"schema"`
+			} `json:"fields"`
+		}
+		status, err := c.doRPC("GET", "/issue/createmeta/"+projectKey+"/issuetypes/"+it.ID, nil, &resp)
+		if err != nil {
+			return nil, err
+		}
+		if status != http.StatusOK {
+			return nil, fmt.Errorf("failed to get createmeta fields for issue type %s, status: %d", it.Name, status)
+		}
+
+		for _, f := range resp.Fields {
+			allowed := make([]string, 0, len(f.AllowedValues))
+			for _, v := range f.AllowedValues {
+				if v.Name != "" {
+					allowed = append(allowed, v.Name)
+				} else {
+					allowed = append(allowed, v.Value)
+				}
+			}
+			fields = append(fields, fieldMeta{
+				Required:      f.Required,
+				Name:          f.Name,
+				Key:           f.FieldID,
+				AllowedValues: allowed,
+				SchemaType:    f.Schema.Type,
+			})
+		}
+	}
+	return fields, nil
+}
+
+func (c *JiraClient) getCreateMetadataLegacy(projectKey, issueType string) ([]fieldMeta, error) {
+	var resp struct {
+		Projects []struct {
+			IssueTypes []struct {
+				Name   string `json:"name"`
+				Fields map[string]struct {
+					Required      bool   `json:"required"`
+					Name          string `json:"name"`
+					AllowedValues []struct {
+						Value string `json:"value"`
+						Name  string `json:"name"`
+					} `json:"allowedValues"`
+					Schema struct {
+						Type string `json:"type"`
+					} `json:"schema"`
+				} `json:"fields"`
+			} `json:"issuetypes"`
+		} `json:"projects"`
+	}
+
+	path := "/issue/createmeta?projectKeys=" + projectKey + "&expand=projects.issuetypes.fields"
+	status, err := c.doRPC("GET", path, nil, &resp)
+	if err != nil {
+		return nil, err
+	}
+	if status != http.StatusOK {
+		return nil, fmt.Errorf("failed to get createmeta, status: %d", status)
+	}
+
+	var fields []fieldMeta
+	for _, project := range resp.Projects {
+		for _, it := range project.IssueTypes {
+			if issueType != "" && !strings.EqualFold(it.Name, issueType) {
+				continue
+			}
+			for key, f := range it.Fields {
+				allowed := make([]string, 0, len(f.AllowedValues))
+				for _, v := range f.AllowedValues {
+					if v.Name != "" {
+						allowed = append(allowed, v.Name)
+					} else {
+						allowed = append(allowed, v.Value)
+					}
+				}
+				fields = append(fields, fieldMeta{
+					Required:      f.Required,
+					Name:          f.Name,
+					Key:           key,
+					AllowedValues: allowed,
+					SchemaType:    f.Schema.Type,
+				})
+			}
+		}
+	}
+	return fields, nil
+}
+
+// getEditMetadata returns the fields an existing issue can be edited to.
+func (c *JiraClient) getEditMetadata(issueKey string) ([]fieldMeta, error) {
+	var resp struct {
+		Fields map[string]struct {
+			Required      bool   `json:"required"`
+			Name          string `json:"name"`
+			AllowedValues []struct {
+				Value string `json:"value"`
+				Name  string `json:"name"`
+			} `json:"allowedValues"`
+			Schema struct {
+				Type string `json:"type"`
+			} `json:"schema"`
+		} `json:"fields"`
+	}
+	status, err := c.doRPC("GET", "/issue/"+issueKey+"/editmeta", nil, &resp)
+	if err != nil {
+		return nil, err
+	}
+	if status != http.StatusOK {
+		return nil, fmt.Errorf("failed to get editmeta, status: %d", status)
+	}
+
+	fields := make([]fieldMeta, 0, len(resp.Fields))
+	for key, f := range resp.Fields {
+		allowed := make([]string, 0, len(f.AllowedValues))
+		for _, v := range f.AllowedValues {
+			if v.Name != "" {
+				allowed = append(allowed, v.Name)
+			} else {
+				allowed = append(allowed, v.Value)
+			}
+		}
+		fields = append(fields, fieldMeta{
+			Required:      f.Required,
+			Name:          f.Name,
+			Key:           key,
+			AllowedValues: allowed,
+			SchemaType:    f.Schema.Type,
+		})
+	}
+	return fields, nil
+}
+
+// fieldNameCache resolves human-readable custom field names (e.g. "Story
+// Points") to their customfield_XXXXX id, caching the /field response so
+// repeated lookups don't re-fetch it.
+type fieldNameCache struct {
+	mu     sync.Mutex
+	byName map[string]string
+	loaded bool
+}
+
+// fieldCaches holds one fieldNameCache per Jira host, shared across every
+// JiraClient built for that host. A JiraClient is constructed fresh on
+// every tool call (see resolveClient), so caching on the client itself would
+// never survive past a single call; keying by host here is what actually
+// makes repeated lookups skip re-fetching /field.
+var fieldCaches = struct {
+	mu     sync.Mutex
+	byHost map[string]*fieldNameCache
+}{byHost: map[string]*fieldNameCache{}}
+
+func fieldCacheFor(baseURL string) *fieldNameCache {
+	fieldCaches.mu.Lock()
+	defer fieldCaches.mu.Unlock()
+	fc, ok := fieldCaches.byHost[baseURL]
+	if !ok {
+		fc = &fieldNameCache{}
+		fieldCaches.byHost[baseURL] = fc
+	}
+	return fc
+}
+
+// resolveFields rewrites the keys of fields from display names to field ids
+// where a match exists in Jira's /field catalog, leaving already-canonical
+// keys (e.g. "customfield_10001", "summary") untouched.
+func (c *JiraClient) resolveFields(fields map[string]interface{}) (map[string]interface{}, error) {
+	if len(fields) == 0 {
+		return fields, nil
+	}
+
+	cache := fieldCacheFor(c.config.URL)
+	if err := cache.load(c); err != nil {
+		return nil, err
+	}
+
+	resolved := make(map[string]interface{}, len(fields))
+	for name, value := range fields {
+		if id, ok := cache.byName[strings.ToLower(name)]; ok {
+			resolved[id] = value
+		} else {
+			resolved[name] = value
+		}
+	}
+	return resolved, nil
+}
+
+func (fc *fieldNameCache) load(c *JiraClient) error {
+	fc.mu.Lock()
+	defer fc.mu.Unlock()
+
+	if fc.loaded {
+		return nil
+	}
+
+	var fields []struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+	}
+	status, err := c.doRPC("GET", "/field", nil, &fields)
+	if err != nil {
+		return err
+	}
+	if status != http.StatusOK {
+		return fmt.Errorf("failed to load field catalog, status: %d", status)
+	}
+
+	fc.byName = make(map[string]string, len(fields))
+	for _, f := range fields {
+		fc.byName[strings.ToLower(f.Name)] = f.ID
+	}
+	fc.loaded = true
+	return nil
+}
+
+// adfDescription wraps a plain-text description in the minimal Atlassian
+// Document Format Jira Cloud v3 requires, so plain strings still render
+// instead of being rejected or shown as raw JSON.
+func adfDescription(text string) map[string]interface{} {
+	return map[string]interface{}{
+		"type":    "doc",
+		"version": 1,
+		"content": []map[string]interface{}{
+			{
+				"type": "paragraph",
+				"content": []map[string]interface{}{
+					{"type": "text", "text": text},
+				},
+			},
+		},
+	}
+}
+
+// registerMetadataTools wires the createmeta/editmeta introspection tools
+// onto server.
+func registerMetadataTools(server *mcp_golang.Server, store ConnectionStore) error {
+	if err := server.RegisterTool("get_create_metadata", "Get the fields (and allowed values) required to create an issue in a project",
+		func(args GetCreateMetadataArgs) (*mcp_golang.ToolResponse, error) {
+			client, err := resolveClient(store, args.JiraConfig)
+			if err != nil {
+				return nil, err
+			}
+			fields, err := client.getCreateMetadata(args.ProjectKey, args.IssueType)
+			if err != nil {
+				return nil, err
+			}
+			return mcp_golang.NewToolResponse(mcp_golang.NewTextContent(string(mustMarshal(fields)))), nil
+		}); err != nil {
+		return err
+	}
+
+	if err := server.RegisterTool("get_edit_metadata", "Get the fields an existing issue can be edited to",
+		func(args GetEditMetadataArgs) (*mcp_golang.ToolResponse, error) {
+			client, err := resolveClient(store, args.JiraConfig)
+			if err != nil {
+				return nil, err
+			}
+			fields, err := client.getEditMetadata(args.IssueKey)
+			if err != nil {
+				return nil, err
+			}
+			return mcp_golang.NewToolResponse(mcp_golang.NewTextContent(string(mustMarshal(fields)))), nil
+		}); err != nil {
+		return err
+	}
+
+	return nil
+}
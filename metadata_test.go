@@ -0,0 +1,39 @@
+package main
+
+import "testing"
+
+func TestAdfDescriptionShape(t *testing.T) {
+	doc := adfDescription("hello world")
+
+	if doc["type"] != "doc" || doc["version"] != 1 {
+		t.Fatalf("adfDescription() top-level shape = %+v, want type=doc version=1", doc)
+	}
+
+	content, ok := doc["content"].([]map[string]interface{})
+	if !ok || len(content) != 1 {
+		t.Fatalf("adfDescription() content = %+v, want one paragraph node", doc["content"])
+	}
+
+	paragraph := content[0]
+	if paragraph["type"] != "paragraph" {
+		t.Fatalf("adfDescription() content[0] type = %v, want paragraph", paragraph["type"])
+	}
+
+	text, ok := paragraph["content"].([]map[string]interface{})
+	if !ok || len(text) != 1 || text[0]["text"] != "hello world" {
+		t.Fatalf("adfDescription() paragraph content = %+v, want a single text node with \"hello world\"", paragraph["content"])
+	}
+}
+
+func TestFieldCacheForReusesSameInstancePerHost(t *testing.T) {
+	a := fieldCacheFor("https://example.atlassian.net")
+	b := fieldCacheFor("https://example.atlassian.net")
+	if a != b {
+		t.Error("fieldCacheFor returned different instances for the same host")
+	}
+
+	c := fieldCacheFor("https://other.atlassian.net")
+	if a == c {
+		t.Error("fieldCacheFor returned the same instance for different hosts")
+	}
+}
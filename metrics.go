@@ -0,0 +1,88 @@
+package main
+
+import (
+	"log"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+var (
+	rpcDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "jira_request_duration_seconds",
+		Help:    "Duration of Jira REST API calls made by this server.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"endpoint"})
+
+	rpcErrors = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "jira_request_errors_total",
+		Help: "Count of Jira REST API calls that returned an error, by code and endpoint.",
+	}, []string{"code", "endpoint"})
+
+	alertsProcessed = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "jira_alert_ingress_processed_total",
+		Help: "Count of Alertmanager webhook groups successfully applied to Jira.",
+	})
+
+	alertProcessingErrors = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "jira_alert_ingress_errors_total",
+		Help: "Count of Alertmanager webhook groups that failed to apply to Jira.",
+	})
+)
+
+// recordRPCDuration observes how long a Jira REST call to path took.
+func recordRPCDuration(path string, seconds float64) {
+	rpcDuration.WithLabelValues(metricsEndpointLabel(path)).Observe(seconds)
+}
+
+// recordRPCError increments the error counter for a failed Jira REST call.
+func recordRPCError(path, code string) {
+	rpcErrors.WithLabelValues(code, metricsEndpointLabel(path)).Inc()
+}
+
+var (
+	issueKeySegment = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9]*-[0-9]+$`)
+	numericSegment  = regexp.MustCompile(`^[0-9]+$`)
+)
+
+// metricsEndpointLabel strips the query string and collapses path segments
+// that vary per-entity down to a low-cardinality label, so e.g.
+// "/issue/PROJ-123/transitions" and "/issue/PROJ-456/transitions" both
+// collapse to "/issue/{key}/transitions", and "/issue/PROJ-1/comment/10001"
+// collapses to "/issue/{key}/comment/{id}", instead of exploding the metric
+// series with one label value per issue/comment touched.
+func metricsEndpointLabel(path string) string {
+	if idx := strings.IndexByte(path, '?'); idx >= 0 {
+		path = path[:idx]
+	}
+
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		switch {
+		case segment == "":
+			continue
+		case issueKeySegment.MatchString(segment):
+			segments[i] = "{key}"
+		case numericSegment.MatchString(segment):
+			segments[i] = "{id}"
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+// serveMetrics starts a small HTTP server exposing Prometheus metrics on
+// addr. It runs alongside the MCP server's own HTTP listener, which owns its
+// endpoint exclusively and can't be shared with additional routes.
+func serveMetrics(addr string) {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.Handler())
+
+	log.Printf("Serving Prometheus metrics on %s/metrics", addr)
+	if err := http.ListenAndServe(addr, mux); err != nil {
+		log.Printf("metrics server error: %v", err)
+	}
+}
@@ -0,0 +1,18 @@
+package main
+
+import "testing"
+
+func TestMetricsEndpointLabelCollapsesIssueKeys(t *testing.T) {
+	cases := map[string]string{
+		"/issue/PROJ-123/transitions": "/issue/{key}/transitions",
+		"/issue/PROJ-456/transitions": "/issue/{key}/transitions",
+		"/issue/PROJ-1/comment/10001": "/issue/{key}/comment/{id}",
+		"/project/PROJ/components":    "/project/PROJ/components",
+		"/search?jql=project%3DPROJ":  "/search",
+	}
+	for in, want := range cases {
+		if got := metricsEndpointLabel(in); got != want {
+			t.Errorf("metricsEndpointLabel(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
@@ -0,0 +1,248 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	mcp_golang "github.com/metoro-io/mcp-golang"
+)
+
+const defaultSearchMaxResults = 50
+
+// SearchIssuesArgs searches issues using JQL, paging through results until
+// exhausted or MaxPages is reached (0 means no cap).
+type SearchIssuesArgs struct {
+	JiraConfig JiraConfig `json:"jira_config" jsonschema:"required,description=Jira connection configuration"`
+	JQL        string     `json:"jql" jsonschema:"required,description=The JQL query to search issues (e.g., 'project = PROJ AND status = Open')"`
+	Fields     []string   `json:"fields" jsonschema:"description=Fields to return per issue; defaults to summary and status if omitted"`
+	Expand     []string   `json:"expand" jsonschema:"description=Optional expand parameters, e.g. 'changelog', 'renderedFields'"`
+	MaxResults int        `json:"max_results" jsonschema:"description=Page size; defaults to 50"`
+	MaxPages   int        `json:"max_pages" jsonschema:"description=Maximum number of pages to fetch; 0 means fetch until exhausted"`
+}
+
+// SearchPage is one page of JQL search results, plus enough pagination
+// metadata for the caller to decide whether to keep going.
+type SearchPage struct {
+	Issues        []map[string]interface{} `json:"issues"`
+	Total         int                      `json:"total,omitempty"`
+	IsLast        bool                     `json:"is_last"`
+	NextPageToken string                   `json:"next_page_token,omitempty"`
+	StartAt       int                      `json:"start_at,omitempty"`
+}
+
+// SearchResult aggregates every page fetched by searchIssues.
+type SearchResult struct {
+	Issues        []map[string]interface{} `json:"issues"`
+	Total         int                      `json:"total,omitempty"`
+	IsLast        bool                     `json:"is_last"`
+	NextPageToken string                   `json:"next_page_token,omitempty"`
+}
+
+// searchIssues runs args.JQL to exhaustion (or MaxPages, if set), using the
+// Cloud enhanced search endpoint (/search/jql, cursor-paginated via
+// nextPageToken) or the Data Center legacy endpoint (/search,
+// startAt/maxResults-paginated) depending on the client's isCloud flag.
+func (c *JiraClient) searchIssues(args SearchIssuesArgs) (*SearchResult, error) {
+	maxResults := args.MaxResults
+	if maxResults <= 0 {
+		maxResults = defaultSearchMaxResults
+	}
+
+	result := &SearchResult{}
+	startAt := 0
+	pageToken := ""
+
+	for page := 0; args.MaxPages <= 0 || page < args.MaxPages; page++ {
+		var sp *SearchPage
+		var err error
+		if c.isCloud {
+			sp, err = c.searchIssuesPageCloud(args, maxResults, pageToken)
+		} else {
+			sp, err = c.searchIssuesPageLegacy(args, maxResults, startAt)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		result.Issues = append(result.Issues, sp.Issues...)
+		result.Total = sp.Total
+		result.IsLast = sp.IsLast
+		result.NextPageToken = sp.NextPageToken
+
+		if sp.IsLast || (c.isCloud && sp.NextPageToken == "") || (!c.isCloud && len(sp.Issues) < maxResults) {
+			break
+		}
+
+		pageToken = sp.NextPageToken
+		startAt += len(sp.Issues)
+	}
+
+	return result, nil
+}
+
+func (c *JiraClient) searchIssuesPageCloud(args SearchIssuesArgs, maxResults int, pageToken string) (*SearchPage, error) {
+	payload := map[string]interface{}{
+		"jql":        args.JQL,
+		"maxResults": maxResults,
+	}
+	if len(args.Fields) > 0 {
+		payload["fields"] = args.Fields
+	}
+	if len(args.Expand) > 0 {
+		payload["expand"] = strings.Join(args.Expand, ",")
+	}
+	if pageToken != "" {
+		payload["nextPageToken"] = pageToken
+	}
+
+	var resp struct {
+		Issues []struct {
+			Key    string                 `json:"key"`
+			Fields map[string]interface{} `json:"fields"`
+		} `json:"issues"`
+		IsLast        bool   `json:"isLast"`
+		NextPageToken string `json:"nextPageToken"`
+	}
+	status, err := c.doRPC("POST", "/search/jql", payload, &resp)
+	if err != nil {
+		return nil, err
+	}
+	if status != http.StatusOK {
+		return nil, fmt.Errorf("failed to search issues, status: %d", status)
+	}
+
+	return &SearchPage{
+		Issues:        flattenIssues(resp.Issues),
+		IsLast:        resp.IsLast,
+		NextPageToken: resp.NextPageToken,
+	}, nil
+}
+
+func (c *JiraClient) searchIssuesPageLegacy(args SearchIssuesArgs, maxResults, startAt int) (*SearchPage, error) {
+	query := url.Values{}
+	query.Set("jql", args.JQL)
+	query.Set("startAt", strconv.Itoa(startAt))
+	query.Set("maxResults", strconv.Itoa(maxResults))
+	if len(args.Fields) > 0 {
+		query.Set("fields", strings.Join(args.Fields, ","))
+	} else {
+		query.Set("fields", "summary,status")
+	}
+	if len(args.Expand) > 0 {
+		query.Set("expand", strings.Join(args.Expand, ","))
+	}
+
+	var resp struct {
+		Issues []struct {
+			Key    string                 `json:"key"`
+			Fields map[string]interface{} `json:"fields"`
+		} `json:"issues"`
+		StartAt    int `json:"startAt"`
+		MaxResults int `json:"maxResults"`
+		Total      int `json:"total"`
+	}
+	status, err := c.doRPC("GET", "/search?"+query.Encode(), nil, &resp)
+	if err != nil {
+		return nil, err
+	}
+	if status != http.StatusOK {
+		return nil, fmt.Errorf("failed to search issues, status: %d", status)
+	}
+
+	return &SearchPage{
+		Issues:  flattenIssues(resp.Issues),
+		Total:   resp.Total,
+		IsLast:  resp.StartAt+len(resp.Issues) >= resp.Total,
+		StartAt: resp.StartAt,
+	}, nil
+}
+
+func flattenIssues(issues []struct {
+	Key    string                 `json:"key"`
+	Fields map[string]interface{} `json:"fields"`
+}) []map[string]interface{} {
+	out := make([]map[string]interface{}, 0, len(issues))
+	for _, issue := range issues {
+		entry := map[string]interface{}{"key": issue.Key}
+		for k, v := range issue.Fields {
+			entry[k] = v
+		}
+		out = append(out, entry)
+	}
+	return out
+}
+
+// registerSearchTools wires search_issues and search_issues_stream onto
+// server.
+func registerSearchTools(server *mcp_golang.Server, store ConnectionStore) error {
+	if err := server.RegisterTool("search_issues", "Search Jira issues using JQL, paging through all results",
+		func(args SearchIssuesArgs) (*mcp_golang.ToolResponse, error) {
+			client, err := resolveClient(store, args.JiraConfig)
+			if err != nil {
+				return nil, err
+			}
+			result, err := client.searchIssues(args)
+			if err != nil {
+				return nil, err
+			}
+			return mcp_golang.NewToolResponse(
+				mcp_golang.NewTextContent(string(mustMarshal(result))),
+			), nil
+		}); err != nil {
+		return err
+	}
+
+	// search_issues_stream fetches the same paginated results as
+	// search_issues, but emits one content block per page instead of
+	// aggregating them, so a caller can start acting on early pages without
+	// waiting for the whole query to finish. The mcp-golang transport this
+	// server uses doesn't expose a server-initiated notification hook, so
+	// this is the closest approximation available: incremental content
+	// within a single response rather than a true push per page.
+	if err := server.RegisterTool("search_issues_stream", "Search Jira issues using JQL, returning one result block per page",
+		func(args SearchIssuesArgs) (*mcp_golang.ToolResponse, error) {
+			client, err := resolveClient(store, args.JiraConfig)
+			if err != nil {
+				return nil, err
+			}
+
+			maxResults := args.MaxResults
+			if maxResults <= 0 {
+				maxResults = defaultSearchMaxResults
+			}
+
+			var contents []*mcp_golang.Content
+			startAt := 0
+			pageToken := ""
+
+			for page := 0; args.MaxPages <= 0 || page < args.MaxPages; page++ {
+				var sp *SearchPage
+				var err error
+				if client.isCloud {
+					sp, err = client.searchIssuesPageCloud(args, maxResults, pageToken)
+				} else {
+					sp, err = client.searchIssuesPageLegacy(args, maxResults, startAt)
+				}
+				if err != nil {
+					return nil, err
+				}
+
+				contents = append(contents, mcp_golang.NewTextContent(string(mustMarshal(sp))))
+
+				if sp.IsLast || (client.isCloud && sp.NextPageToken == "") || (!client.isCloud && len(sp.Issues) < maxResults) {
+					break
+				}
+				pageToken = sp.NextPageToken
+				startAt += len(sp.Issues)
+			}
+
+			return mcp_golang.NewToolResponse(contents...), nil
+		}); err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,25 @@
+package main
+
+import "testing"
+
+func TestFlattenIssuesMergesFieldsWithKey(t *testing.T) {
+	issues := []struct {
+		Key    string                 `json:"key"`
+		Fields map[string]interface{} `json:"fields"`
+	}{
+		{Key: "PROJ-1", Fields: map[string]interface{}{"summary": "first"}},
+		{Key: "PROJ-2", Fields: map[string]interface{}{"summary": "second"}},
+	}
+
+	flattened := flattenIssues(issues)
+
+	if len(flattened) != 2 {
+		t.Fatalf("flattenIssues returned %d entries, want 2", len(flattened))
+	}
+	if flattened[0]["key"] != "PROJ-1" || flattened[0]["summary"] != "first" {
+		t.Errorf("flattenIssues()[0] = %+v, want key=PROJ-1 summary=first", flattened[0])
+	}
+	if flattened[1]["key"] != "PROJ-2" || flattened[1]["summary"] != "second" {
+		t.Errorf("flattenIssues()[1] = %+v, want key=PROJ-2 summary=second", flattened[1])
+	}
+}
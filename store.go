@@ -0,0 +1,233 @@
+package main
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+// Connection is a persisted set of Jira credentials, looked up by a
+// caller-supplied connection_id so tools can accept {connection_id, ...}
+// instead of repeating a full JiraConfig on every call.
+type Connection struct {
+	ID        string            `json:"id"`
+	URL       string            `json:"url"`
+	IsCloud   bool              `json:"is_cloud"`
+	AuthKind  string            `json:"auth_kind"`
+	Secrets   map[string]string `json:"secrets"`
+	CreatedAt time.Time         `json:"created_at"`
+}
+
+// ConnectionStore persists Connections. Secret fields are expected to be
+// encrypted at rest by the implementation before they touch disk.
+type ConnectionStore interface {
+	Save(conn Connection) error
+	Get(id string) (Connection, error)
+	List() ([]Connection, error)
+	Delete(id string) error
+}
+
+// ErrConnectionNotFound is returned by ConnectionStore.Get/Delete when the
+// requested connection_id is unknown.
+var ErrConnectionNotFound = errors.New("connection not found")
+
+// FileConnectionStore persists connections as a single encrypted-at-rest
+// JSON file. It's deliberately simple (no external DB dependency) to match
+// the rest of this server; a BoltDB-backed store can satisfy the same
+// ConnectionStore interface if a deployment needs concurrent multi-process
+// access.
+type FileConnectionStore struct {
+	path string
+	key  [32]byte
+
+	mu    sync.Mutex
+	conns map[string]Connection
+}
+
+// NewFileConnectionStore opens (or creates) the connection store at path,
+// encrypting secret fields with key. key must be 32 bytes (AES-256); see
+// DeriveStoreKey for turning an operator-supplied passphrase into one.
+func NewFileConnectionStore(path string, key [32]byte) (*FileConnectionStore, error) {
+	s := &FileConnectionStore{path: path, key: key, conns: map[string]Connection{}}
+	if err := s.load(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// DeriveStoreKey derives a 32-byte AES key from an operator-supplied
+// passphrase (e.g. the JIRA_MCP_STORE_KEY environment variable).
+func DeriveStoreKey(passphrase string) [32]byte {
+	return sha256.Sum256([]byte(passphrase))
+}
+
+func (s *FileConnectionStore) load() error {
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("read connection store: %w", err)
+	}
+
+	var raw map[string]Connection
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return fmt.Errorf("parse connection store: %w", err)
+	}
+
+	for id, conn := range raw {
+		decrypted, err := s.decryptSecrets(conn.Secrets)
+		if err != nil {
+			return fmt.Errorf("decrypt connection %s: %w", id, err)
+		}
+		conn.Secrets = decrypted
+		s.conns[id] = conn
+	}
+	return nil
+}
+
+func (s *FileConnectionStore) persist() error {
+	encrypted := make(map[string]Connection, len(s.conns))
+	for id, conn := range s.conns {
+		enc, err := s.encryptSecrets(conn.Secrets)
+		if err != nil {
+			return fmt.Errorf("encrypt connection %s: %w", id, err)
+		}
+		conn.Secrets = enc
+		encrypted[id] = conn
+	}
+
+	data, err := json.MarshalIndent(encrypted, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal connection store: %w", err)
+	}
+	return os.WriteFile(s.path, data, 0o600)
+}
+
+func (s *FileConnectionStore) Save(conn Connection) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if conn.ID == "" {
+		return errors.New("connection id is required")
+	}
+	if conn.CreatedAt.IsZero() {
+		conn.CreatedAt = time.Now()
+	}
+	s.conns[conn.ID] = conn
+	return s.persist()
+}
+
+func (s *FileConnectionStore) Get(id string) (Connection, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	conn, ok := s.conns[id]
+	if !ok {
+		return Connection{}, ErrConnectionNotFound
+	}
+	return conn, nil
+}
+
+func (s *FileConnectionStore) List() ([]Connection, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	out := make([]Connection, 0, len(s.conns))
+	for _, conn := range s.conns {
+		out = append(out, conn)
+	}
+	return out, nil
+}
+
+func (s *FileConnectionStore) Delete(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.conns[id]; !ok {
+		return ErrConnectionNotFound
+	}
+	delete(s.conns, id)
+	return s.persist()
+}
+
+// encryptSecrets/decryptSecrets apply AES-256-GCM to each secret value
+// independently, so a partially-corrupt store only loses the affected field.
+func (s *FileConnectionStore) encryptSecrets(secrets map[string]string) (map[string]string, error) {
+	gcm, err := s.gcm()
+	if err != nil {
+		return nil, err
+	}
+
+	out := make(map[string]string, len(secrets))
+	for k, v := range secrets {
+		nonce := make([]byte, gcm.NonceSize())
+		if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+			return nil, err
+		}
+		sealed := gcm.Seal(nonce, nonce, []byte(v), nil)
+		out[k] = base64.StdEncoding.EncodeToString(sealed)
+	}
+	return out, nil
+}
+
+func (s *FileConnectionStore) decryptSecrets(secrets map[string]string) (map[string]string, error) {
+	gcm, err := s.gcm()
+	if err != nil {
+		return nil, err
+	}
+
+	out := make(map[string]string, len(secrets))
+	for k, v := range secrets {
+		raw, err := base64.StdEncoding.DecodeString(v)
+		if err != nil {
+			return nil, err
+		}
+		nonceSize := gcm.NonceSize()
+		if len(raw) < nonceSize {
+			return nil, errors.New("ciphertext too short")
+		}
+		nonce, ciphertext := raw[:nonceSize], raw[nonceSize:]
+		plain, err := gcm.Open(nil, nonce, ciphertext, nil)
+		if err != nil {
+			return nil, err
+		}
+		out[k] = string(plain)
+	}
+	return out, nil
+}
+
+func (s *FileConnectionStore) gcm() (cipher.AEAD, error) {
+	block, err := aes.NewCipher(s.key[:])
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// encodeRSAPrivateKeyPEM/decodeRSAPrivateKeyPEM let an OAuth1Provider's
+// private key be stored as a PEM string inside Connection.Secrets.
+func encodeRSAPrivateKeyPEM(key *rsa.PrivateKey) string {
+	block := &pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)}
+	return string(pem.EncodeToMemory(block))
+}
+
+func decodeRSAPrivateKeyPEM(data string) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode([]byte(data))
+	if block == nil {
+		return nil, errors.New("invalid PEM private key")
+	}
+	return x509.ParsePKCS1PrivateKey(block.Bytes)
+}
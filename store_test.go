@@ -0,0 +1,82 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestFileConnectionStoreEncryptDecryptRoundTrip(t *testing.T) {
+	store, err := NewFileConnectionStore(filepath.Join(t.TempDir(), "connections.json"), DeriveStoreKey("test-passphrase"))
+	if err != nil {
+		t.Fatalf("NewFileConnectionStore() error = %v", err)
+	}
+
+	conn := Connection{
+		ID:       "jira-prod",
+		URL:      "https://example.atlassian.net",
+		AuthKind: "basic",
+		Secrets:  map[string]string{"email": "bot@example.com", "api_key": "super-secret-token"},
+	}
+	if err := store.Save(conn); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	reopened, err := NewFileConnectionStore(store.path, DeriveStoreKey("test-passphrase"))
+	if err != nil {
+		t.Fatalf("reopening store error = %v", err)
+	}
+
+	got, err := reopened.Get("jira-prod")
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if got.Secrets["email"] != "bot@example.com" || got.Secrets["api_key"] != "super-secret-token" {
+		t.Errorf("Get() secrets = %+v, want round-tripped plaintext", got.Secrets)
+	}
+}
+
+func TestFileConnectionStoreWrongKeyFailsToDecrypt(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "connections.json")
+	store, err := NewFileConnectionStore(path, DeriveStoreKey("correct-passphrase"))
+	if err != nil {
+		t.Fatalf("NewFileConnectionStore() error = %v", err)
+	}
+	if err := store.Save(Connection{ID: "c1", Secrets: map[string]string{"api_key": "secret"}}); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	if _, err := NewFileConnectionStore(path, DeriveStoreKey("wrong-passphrase")); err == nil {
+		t.Error("NewFileConnectionStore() with the wrong key = nil error, want a decrypt failure")
+	}
+}
+
+func TestFileConnectionStoreGetMissingReturnsErrConnectionNotFound(t *testing.T) {
+	store, err := NewFileConnectionStore(filepath.Join(t.TempDir(), "connections.json"), DeriveStoreKey("test-passphrase"))
+	if err != nil {
+		t.Fatalf("NewFileConnectionStore() error = %v", err)
+	}
+
+	if _, err := store.Get("does-not-exist"); err != ErrConnectionNotFound {
+		t.Errorf("Get() error = %v, want ErrConnectionNotFound", err)
+	}
+}
+
+func TestFileConnectionStoreDelete(t *testing.T) {
+	store, err := NewFileConnectionStore(filepath.Join(t.TempDir(), "connections.json"), DeriveStoreKey("test-passphrase"))
+	if err != nil {
+		t.Fatalf("NewFileConnectionStore() error = %v", err)
+	}
+	if err := store.Save(Connection{ID: "c1", Secrets: map[string]string{"api_key": "secret"}}); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	if err := store.Delete("c1"); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if _, err := store.Get("c1"); err != ErrConnectionNotFound {
+		t.Errorf("Get() after Delete() error = %v, want ErrConnectionNotFound", err)
+	}
+	if err := store.Delete("c1"); err != ErrConnectionNotFound {
+		t.Errorf("Delete() of an already-deleted id error = %v, want ErrConnectionNotFound", err)
+	}
+}
@@ -0,0 +1,400 @@
+package main
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	mcp_golang "github.com/metoro-io/mcp-golang"
+)
+
+// IssueEvent is the normalized shape this server delivers to subscribers,
+// whether it came from a real Atlassian webhook POST or the Data Center
+// polling fallback.
+type IssueEvent struct {
+	Type       string                 `json:"type"`
+	IssueKey   string                 `json:"issue_key"`
+	ProjectKey string                 `json:"project_key,omitempty"`
+	Timestamp  time.Time              `json:"timestamp"`
+	Raw        map[string]interface{} `json:"raw,omitempty"`
+}
+
+// subscription tracks one subscribe_issues call: a JQL filter, the event
+// types it cares about, and a buffer of events waiting to be drained by
+// poll_subscription.
+type subscription struct {
+	ID          string
+	JQL         string
+	ProjectKeys map[string]bool // parsed from "project = X" / "project in (...)" when possible
+	Events      map[string]bool // empty means "all event types"
+
+	config   JiraConfig // used only by the Data Center polling fallback
+	lastSeen time.Time
+	stop     chan struct{}
+
+	mu     sync.Mutex
+	buffer []IssueEvent
+}
+
+const subscriptionBufferLimit = 500
+
+// subscriptionRegistry is the process-wide set of active subscriptions.
+// mcp-golang's HTTP transport doesn't expose a server-initiated notification
+// hook (see search_issues_stream's comment for the same constraint), so
+// delivery here is poll-based: webhook POSTs and the DC polling fallback
+// both just append to a subscription's buffer for poll_subscription to
+// drain.
+var subscriptionRegistry = struct {
+	mu   sync.Mutex
+	subs map[string]*subscription
+}{subs: map[string]*subscription{}}
+
+var projectFilterPattern = regexp.MustCompile(`(?i)project\s*(=|in)\s*\(?\s*"?([A-Za-z0-9_,\s"]+?)"?\s*\)?(\s|$|and|or)`)
+
+// parseProjectKeys extracts project keys from simple JQL filters like
+// `project = PROJ` or `project in (PROJ, OTHER)`. It's a best-effort
+// heuristic, not a JQL parser: JQL without a leading project clause matches
+// every event instead of being silently dropped.
+func parseProjectKeys(jql string) map[string]bool {
+	match := projectFilterPattern.FindStringSubmatch(jql)
+	if match == nil {
+		return nil
+	}
+
+	keys := map[string]bool{}
+	for _, raw := range strings.Split(match[2], ",") {
+		key := strings.Trim(strings.TrimSpace(raw), `"`)
+		if key != "" {
+			keys[strings.ToUpper(key)] = true
+		}
+	}
+	return keys
+}
+
+func registerSubscription(sub *subscription) {
+	subscriptionRegistry.mu.Lock()
+	defer subscriptionRegistry.mu.Unlock()
+	subscriptionRegistry.subs[sub.ID] = sub
+}
+
+func unregisterSubscription(id string) (*subscription, bool) {
+	subscriptionRegistry.mu.Lock()
+	defer subscriptionRegistry.mu.Unlock()
+	sub, ok := subscriptionRegistry.subs[id]
+	if ok {
+		delete(subscriptionRegistry.subs, id)
+	}
+	return sub, ok
+}
+
+// dispatchEvent fans event out to every subscription whose project/event
+// filters match.
+func dispatchEvent(event IssueEvent) {
+	subscriptionRegistry.mu.Lock()
+	subs := make([]*subscription, 0, len(subscriptionRegistry.subs))
+	for _, sub := range subscriptionRegistry.subs {
+		subs = append(subs, sub)
+	}
+	subscriptionRegistry.mu.Unlock()
+
+	for _, sub := range subs {
+		if !sub.matches(event) {
+			continue
+		}
+		sub.push(event)
+	}
+}
+
+func (s *subscription) matches(event IssueEvent) bool {
+	if len(s.Events) > 0 && !s.Events[event.Type] {
+		return false
+	}
+	if len(s.ProjectKeys) > 0 && event.ProjectKey != "" && !s.ProjectKeys[strings.ToUpper(event.ProjectKey)] {
+		return false
+	}
+	return true
+}
+
+func (s *subscription) push(event IssueEvent) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.buffer = append(s.buffer, event)
+	if len(s.buffer) > subscriptionBufferLimit {
+		s.buffer = s.buffer[len(s.buffer)-subscriptionBufferLimit:]
+	}
+}
+
+// drain removes and returns up to max buffered events (0 means no limit).
+func (s *subscription) drain(max int) []IssueEvent {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if max <= 0 || max > len(s.buffer) {
+		max = len(s.buffer)
+	}
+	events := s.buffer[:max]
+	s.buffer = s.buffer[max:]
+	return events
+}
+
+// SubscribeIssuesArgs registers interest in issue events matching jql.
+type SubscribeIssuesArgs struct {
+	JiraConfig JiraConfig `json:"jira_config" jsonschema:"required,description=Jira connection configuration, used by the Data Center polling fallback"`
+	JQL        string     `json:"jql" jsonschema:"required,description=JQL used to filter events, e.g. 'project = PROJ'"`
+	Events     []string   `json:"events" jsonschema:"description=Event types to include (e.g. jira:issue_created, jira:issue_updated, comment_created); defaults to all"`
+}
+
+// UnsubscribeIssuesArgs removes a previously created subscription.
+type UnsubscribeIssuesArgs struct {
+	SubscriptionID string `json:"subscription_id" jsonschema:"required,description=The id returned by subscribe_issues"`
+}
+
+// PollSubscriptionArgs drains buffered events from a subscription.
+type PollSubscriptionArgs struct {
+	SubscriptionID string `json:"subscription_id" jsonschema:"required,description=The id returned by subscribe_issues"`
+	MaxEvents      int    `json:"max_events" jsonschema:"description=Maximum number of events to return; 0 means all buffered events"`
+}
+
+// RegisterWebhookArgs creates a webhook in Jira pointing back at this
+// server's /jira/webhook endpoint.
+type RegisterWebhookArgs struct {
+	JiraConfig  JiraConfig `json:"jira_config" jsonschema:"required,description=Jira connection configuration"`
+	Name        string     `json:"name" jsonschema:"required,description=A name for the webhook, shown in Jira's admin UI"`
+	CallbackURL string     `json:"callback_url" jsonschema:"required,description=The externally reachable URL of this server's /jira/webhook endpoint"`
+	JQL         string     `json:"jql" jsonschema:"description=Optional JQL filter; Jira only delivers events for matching issues"`
+	Events      []string   `json:"events" jsonschema:"required,description=Event types to subscribe to, e.g. jira:issue_created, jira:issue_updated, comment_created"`
+}
+
+func registerWebhook(client *JiraClient, args RegisterWebhookArgs) error {
+	payload := map[string]interface{}{
+		"name":   args.Name,
+		"url":    args.CallbackURL,
+		"events": args.Events,
+	}
+	if args.JQL != "" {
+		payload["filters"] = map[string]string{"issue-related-events-section": args.JQL}
+	}
+
+	status, err := client.doRPC("POST", "/webhooks/1.0/webhook", payload, nil)
+	if err != nil {
+		return err
+	}
+	if status != http.StatusOK && status != http.StatusCreated {
+		return fmt.Errorf("failed to register webhook, status: %d", status)
+	}
+	return nil
+}
+
+// startPollingFallback runs until sub.stop is closed, periodically
+// re-running sub.JQL restricted to issues updated since the last poll and
+// emitting synthetic events for anything new. This covers Data Center
+// instances that don't reliably deliver webhooks.
+func startPollingFallback(store ConnectionStore, sub *subscription, interval time.Duration) {
+	sub.lastSeen = time.Now()
+	client, err := resolveClient(store, sub.config)
+	if err != nil {
+		log.Printf("polling fallback for subscription %s failed to resolve client: %v", sub.ID, err)
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-sub.stop:
+			return
+		case <-ticker.C:
+			since := sub.lastSeen.Format("2006-01-02 15:04")
+			jql := fmt.Sprintf("(%s) AND updated >= \"%s\"", sub.JQL, since)
+
+			result, err := client.searchIssues(SearchIssuesArgs{
+				JiraConfig: sub.config,
+				JQL:        jql,
+				Fields:     []string{"project", "updated"},
+			})
+			if err != nil {
+				log.Printf("polling fallback for subscription %s failed: %v", sub.ID, err)
+				continue
+			}
+
+			for _, issue := range result.Issues {
+				key, _ := issue["key"].(string)
+				projectKey := ""
+				if project, ok := issue["project"].(map[string]interface{}); ok {
+					projectKey, _ = project["key"].(string)
+				}
+				dispatchEvent(IssueEvent{
+					Type:       "jira:issue_updated",
+					IssueKey:   key,
+					ProjectKey: projectKey,
+					Timestamp:  time.Now(),
+					Raw:        issue,
+				})
+			}
+			sub.lastSeen = time.Now()
+		}
+	}
+}
+
+// handleWebhook verifies sharedSecret against the X-Jira-Webhook-Secret
+// header (constant-time comparison) and fans the decoded event out to
+// subscribers.
+func handleWebhook(sharedSecret string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		if sharedSecret != "" {
+			got := r.Header.Get("X-Jira-Webhook-Secret")
+			if !hmac.Equal([]byte(sha256Sum(got)), []byte(sha256Sum(sharedSecret))) {
+				http.Error(w, "invalid webhook secret", http.StatusUnauthorized)
+				return
+			}
+		}
+
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, "failed to read body", http.StatusBadRequest)
+			return
+		}
+
+		var payload struct {
+			WebhookEvent string `json:"webhookEvent"`
+			Issue        struct {
+				Key    string `json:"key"`
+				Fields struct {
+					Project struct {
+						Key string `json:"key"`
+					} `json:"project"`
+				} `json:"fields"`
+			} `json:"issue"`
+		}
+		if err := json.Unmarshal(body, &payload); err != nil {
+			http.Error(w, "invalid payload", http.StatusBadRequest)
+			return
+		}
+
+		var raw map[string]interface{}
+		_ = json.Unmarshal(body, &raw)
+
+		dispatchEvent(IssueEvent{
+			Type:       payload.WebhookEvent,
+			IssueKey:   payload.Issue.Key,
+			ProjectKey: payload.Issue.Fields.Project.Key,
+			Timestamp:  time.Now(),
+			Raw:        raw,
+		})
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func sha256Sum(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	return string(sum[:])
+}
+
+// serveWebhook starts a small HTTP server exposing the /jira/webhook
+// receiver on addr, mirroring how /metrics runs alongside the MCP server's
+// own HTTP listener.
+func serveWebhook(addr, sharedSecret string) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/jira/webhook", handleWebhook(sharedSecret))
+
+	log.Printf("Serving Jira webhook receiver on %s/jira/webhook", addr)
+	if err := http.ListenAndServe(addr, mux); err != nil {
+		log.Printf("webhook server error: %v", err)
+	}
+}
+
+const pollingFallbackInterval = 60 * time.Second
+
+// registerWebhookTools wires subscribe_issues, unsubscribe_issues,
+// poll_subscription, and register_webhook onto server.
+func registerWebhookTools(server *mcp_golang.Server, store ConnectionStore) error {
+	if err := server.RegisterTool("subscribe_issues", "Subscribe to Jira issue events matching a JQL filter; drain them with poll_subscription",
+		func(args SubscribeIssuesArgs) (*mcp_golang.ToolResponse, error) {
+			sub := &subscription{
+				ID:          oauthNonce(),
+				JQL:         args.JQL,
+				ProjectKeys: parseProjectKeys(args.JQL),
+				config:      args.JiraConfig,
+				stop:        make(chan struct{}),
+			}
+			if len(args.Events) > 0 {
+				sub.Events = map[string]bool{}
+				for _, e := range args.Events {
+					sub.Events[e] = true
+				}
+			}
+
+			registerSubscription(sub)
+
+			// Data Center instances often don't have webhook delivery
+			// configured or reachable from this server, so every
+			// subscription also gets a JQL diff loop as a fallback; real
+			// webhook deliveries for the same issues are simply
+			// deduplicated by the caller polling both.
+			if !strings.Contains(strings.ToLower(sub.config.URL), ".atlassian.net") {
+				go startPollingFallback(store, sub, pollingFallbackInterval)
+			}
+
+			return mcp_golang.NewToolResponse(mcp_golang.NewTextContent(fmt.Sprintf("Subscribed: %s", sub.ID))), nil
+		}); err != nil {
+		return err
+	}
+
+	if err := server.RegisterTool("unsubscribe_issues", "Remove a subscription created by subscribe_issues",
+		func(args UnsubscribeIssuesArgs) (*mcp_golang.ToolResponse, error) {
+			sub, ok := unregisterSubscription(args.SubscriptionID)
+			if !ok {
+				return nil, fmt.Errorf("subscription not found: %s", args.SubscriptionID)
+			}
+			close(sub.stop)
+			return mcp_golang.NewToolResponse(mcp_golang.NewTextContent(fmt.Sprintf("Unsubscribed: %s", args.SubscriptionID))), nil
+		}); err != nil {
+		return err
+	}
+
+	if err := server.RegisterTool("poll_subscription", "Drain buffered issue events for a subscription",
+		func(args PollSubscriptionArgs) (*mcp_golang.ToolResponse, error) {
+			subscriptionRegistry.mu.Lock()
+			sub, ok := subscriptionRegistry.subs[args.SubscriptionID]
+			subscriptionRegistry.mu.Unlock()
+			if !ok {
+				return nil, fmt.Errorf("subscription not found: %s", args.SubscriptionID)
+			}
+
+			events := sub.drain(args.MaxEvents)
+			return mcp_golang.NewToolResponse(mcp_golang.NewTextContent(string(mustMarshal(events)))), nil
+		}); err != nil {
+		return err
+	}
+
+	if err := server.RegisterTool("register_webhook", "Create a webhook in Jira that delivers events to this server's /jira/webhook endpoint",
+		func(args RegisterWebhookArgs) (*mcp_golang.ToolResponse, error) {
+			client, err := resolveClient(store, args.JiraConfig)
+			if err != nil {
+				return nil, err
+			}
+			if err := registerWebhook(client, args); err != nil {
+				return nil, err
+			}
+			return mcp_golang.NewToolResponse(mcp_golang.NewTextContent(fmt.Sprintf("Registered webhook: %s", args.Name))), nil
+		}); err != nil {
+		return err
+	}
+
+	return nil
+}
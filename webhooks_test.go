@@ -0,0 +1,28 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseProjectKeysEquals(t *testing.T) {
+	got := parseProjectKeys(`project = PROJ`)
+	want := map[string]bool{"PROJ": true}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parseProjectKeys(%q) = %v, want %v", `project = PROJ`, got, want)
+	}
+}
+
+func TestParseProjectKeysIn(t *testing.T) {
+	got := parseProjectKeys(`project in (PROJ,OTHER) and status=Open`)
+	want := map[string]bool{"PROJ": true, "OTHER": true}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parseProjectKeys(...) = %v, want %v", got, want)
+	}
+}
+
+func TestParseProjectKeysNoProjectClause(t *testing.T) {
+	if got := parseProjectKeys(`status = Open`); got != nil {
+		t.Errorf("parseProjectKeys(%q) = %v, want nil", `status = Open`, got)
+	}
+}